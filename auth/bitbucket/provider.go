@@ -0,0 +1,195 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+
+	"github.com/concourse/atc/auth/verifier"
+)
+
+const ProviderName = "bitbucket"
+
+const cloudHost = "https://bitbucket.org"
+const cloudAPI = "https://api.bitbucket.org/2.0"
+
+// Provider authenticates against either BitBucket Cloud or a self-hosted
+// BitBucket Server, accepting a user if they belong to the configured
+// team (Cloud) or project (Server).
+type Provider struct {
+	*oauth2.Config
+	verifier.Verifier
+}
+
+// NewProvider configures a Provider for BitBucket Cloud. serverHost is
+// empty in this mode; authorization is done against the given
+// workspace/team name.
+func NewProvider(team, clientID, clientSecret, redirectURL string) Provider {
+	return Provider{
+		Config: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  cloudHost + "/site/oauth2/authorize",
+				TokenURL: cloudHost + "/site/oauth2/access_token",
+			},
+			RedirectURL: redirectURL,
+		},
+		Verifier: NewTeamVerifier(cloudAPI, team),
+	}
+}
+
+// NewServerProvider configures a Provider for a self-hosted BitBucket
+// Server instance, authorizing against the given project key.
+func NewServerProvider(serverHost, project, clientID, clientSecret, redirectURL string) Provider {
+	return Provider{
+		Config: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  serverHost + "/rest/oauth2/latest/authorize",
+				TokenURL: serverHost + "/rest/oauth2/latest/token",
+			},
+			RedirectURL: redirectURL,
+		},
+		Verifier: NewProjectVerifier(serverHost+"/rest/api/1.0", project),
+	}
+}
+
+type TeamVerifier struct {
+	apiHost string
+	team    string
+}
+
+func NewTeamVerifier(apiHost, team string) TeamVerifier {
+	return TeamVerifier{apiHost: apiHost, team: team}
+}
+
+// Verify checks that the user authenticated by httpClient is themselves a
+// member of the configured workspace, not merely that the workspace has
+// members at all. It first resolves the authenticated user's username via
+// GET /user, then asks specifically whether that user is a workspace
+// member.
+func (verifier TeamVerifier) Verify(httpClient *http.Client) (bool, error) {
+	username, err := currentUsername(httpClient, verifier.apiHost)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := httpClient.Get(verifier.apiHost + "/workspaces/" + verifier.team + "/members/" + username)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch bitbucket workspace membership: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status from bitbucket: %s", resp.Status)
+	}
+
+	return true, nil
+}
+
+func currentUsername(httpClient *http.Client, apiHost string) (string, error) {
+	resp, err := httpClient.Get(apiHost + "/user")
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch bitbucket user: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status from bitbucket: %s", resp.Status)
+	}
+
+	var user struct {
+		Username string `json:"username"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return "", fmt.Errorf("failed to decode bitbucket user: %s", err)
+	}
+
+	return user.Username, nil
+}
+
+type ProjectVerifier struct {
+	apiHost string
+	project string
+}
+
+func NewProjectVerifier(apiHost, project string) ProjectVerifier {
+	return ProjectVerifier{apiHost: apiHost, project: project}
+}
+
+// Verify checks that the user authenticated by httpClient specifically
+// has been granted a permission on the configured project, not merely
+// that the project exists (which any authenticated user on the server
+// could see). BitBucket Server has no single "am I a member" endpoint,
+// so this resolves the authenticated username via /plugins/servlet/applinks/whoami
+// and then filters the project's permitted users down to that name.
+func (verifier ProjectVerifier) Verify(httpClient *http.Client) (bool, error) {
+	username, err := currentServerUsername(httpClient, verifier.apiHost)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := httpClient.Get(verifier.apiHost + "/projects/" + verifier.project + "/permissions/users?filter=" + username)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch bitbucket project permissions: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status from bitbucket: %s", resp.Status)
+	}
+
+	var page struct {
+		Values []struct {
+			User struct {
+				Name string `json:"name"`
+			} `json:"user"`
+		} `json:"values"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return false, fmt.Errorf("failed to decode bitbucket project permissions: %s", err)
+	}
+
+	for _, grant := range page.Values {
+		if grant.User.Name == username {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func currentServerUsername(httpClient *http.Client, apiHost string) (string, error) {
+	root := strings.TrimSuffix(apiHost, "/rest/api/1.0")
+
+	resp, err := httpClient.Get(root + "/plugins/servlet/applinks/whoami")
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch bitbucket user: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status from bitbucket: %s", resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read bitbucket user: %s", err)
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
@@ -0,0 +1,172 @@
+package ldap
+
+import (
+	"fmt"
+	"net/http"
+
+	ldapapi "gopkg.in/ldap.v2"
+
+	"github.com/pivotal-golang/lager"
+)
+
+// Validator authenticates the basic-auth credentials on each request
+// against an LDAP or Active Directory server: it binds as Config.BindDN to
+// search for the user's DN under Config.UserSearchBase, then re-binds as
+// that DN with the request's password to confirm it. Unlike the OAuth
+// providers in auth/github, auth/gitlab, etc., there's no redirect dance;
+// this plugs straight into constructValidator alongside
+// auth.BasicAuthValidator.
+type Validator struct {
+	Logger lager.Logger
+
+	Config Config
+}
+
+// Config holds everything needed to bind and search an LDAP directory.
+type Config struct {
+	Host string
+	Port int
+
+	Insecure bool
+
+	// BindDN and BindPassword are used for the initial search; they may
+	// be empty if the server allows anonymous search.
+	BindDN       string
+	BindPassword string
+
+	UserSearchBase   string
+	UserSearchFilter string // e.g. "(uid=%s)", with %s replaced by the submitted username
+
+	GroupSearchBase string
+}
+
+// IsAuthenticated confirms the request's basic-auth credentials bind
+// successfully against the directory. It does not consult
+// GroupSearchBase at all: group membership is an authorization concern,
+// not an authentication one, and gating login on it meant a directory
+// with GroupSearchBase left unset (the default) rejected every user
+// regardless of how correct their credentials were. Whatever consumes
+// this Validator should use Groups, below, if it wants to restrict access
+// by group membership.
+func (validator Validator) IsAuthenticated(r *http.Request) bool {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+
+	_, err := validator.bind(username, password)
+	if err != nil {
+		validator.Logger.Error("failed-to-authenticate", err, lager.Data{"username": username})
+		return false
+	}
+
+	return true
+}
+
+// bind confirms username/password are a valid bind and returns that
+// user's DN.
+func (validator Validator) bind(username, password string) (string, error) {
+	conn, err := validator.dial()
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to ldap server: %s", err)
+	}
+	defer conn.Close()
+
+	if validator.Config.BindDN != "" {
+		if err := conn.Bind(validator.Config.BindDN, validator.Config.BindPassword); err != nil {
+			return "", fmt.Errorf("failed to bind as search user: %s", err)
+		}
+	}
+
+	userDN, err := validator.searchUser(conn, username)
+	if err != nil {
+		return "", err
+	}
+
+	if err := conn.Bind(userDN, password); err != nil {
+		return "", fmt.Errorf("failed to bind as %s: %s", userDN, err)
+	}
+
+	return userDN, nil
+}
+
+// Groups returns the DNs of the groups (under GroupSearchBase) username
+// is a member of, for callers that want to gate authorization (not
+// authentication) on directory group membership.
+func (validator Validator) Groups(username, password string) ([]string, error) {
+	conn, err := validator.dial()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ldap server: %s", err)
+	}
+	defer conn.Close()
+
+	if validator.Config.BindDN != "" {
+		if err := conn.Bind(validator.Config.BindDN, validator.Config.BindPassword); err != nil {
+			return nil, fmt.Errorf("failed to bind as search user: %s", err)
+		}
+	}
+
+	userDN, err := validator.searchUser(conn, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.Bind(userDN, password); err != nil {
+		return nil, fmt.Errorf("failed to bind as %s: %s", userDN, err)
+	}
+
+	return validator.searchGroups(conn, userDN)
+}
+
+func (validator Validator) dial() (*ldapapi.Conn, error) {
+	addr := fmt.Sprintf("%s:%d", validator.Config.Host, validator.Config.Port)
+
+	if validator.Config.Insecure {
+		return ldapapi.Dial("tcp", addr)
+	}
+
+	return ldapapi.DialTLS("tcp", addr, nil)
+}
+
+func (validator Validator) searchUser(conn *ldapapi.Conn, username string) (string, error) {
+	filter := fmt.Sprintf(validator.Config.UserSearchFilter, ldapapi.EscapeFilter(username))
+
+	result, err := conn.Search(ldapapi.NewSearchRequest(
+		validator.Config.UserSearchBase,
+		ldapapi.ScopeWholeSubtree, ldapapi.NeverDerefAliases, 0, 0, false,
+		filter,
+		[]string{"dn"},
+		nil,
+	))
+	if err != nil {
+		return "", fmt.Errorf("failed to search for user: %s", err)
+	}
+
+	if len(result.Entries) != 1 {
+		return "", fmt.Errorf("expected exactly one entry for %s, got %d", username, len(result.Entries))
+	}
+
+	return result.Entries[0].DN, nil
+}
+
+func (validator Validator) searchGroups(conn *ldapapi.Conn, userDN string) ([]string, error) {
+	filter := fmt.Sprintf("(member=%s)", ldapapi.EscapeFilter(userDN))
+
+	result, err := conn.Search(ldapapi.NewSearchRequest(
+		validator.Config.GroupSearchBase,
+		ldapapi.ScopeWholeSubtree, ldapapi.NeverDerefAliases, 0, 0, false,
+		filter,
+		[]string{"dn"},
+		nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for groups: %s", err)
+	}
+
+	groups := make([]string, len(result.Entries))
+	for i, entry := range result.Entries {
+		groups[i] = entry.DN
+	}
+
+	return groups, nil
+}
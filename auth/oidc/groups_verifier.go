@@ -0,0 +1,61 @@
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// GroupsVerifier accepts any user whose userinfo response has a non-empty
+// groupsClaim. It doesn't look at which groups those are; it's a binary
+// allow/deny, same as the GitLab/BitBucket verifiers in this series.
+type GroupsVerifier struct {
+	userinfoEndpoint string
+	groupsClaim      string
+}
+
+func NewGroupsVerifier(userinfoEndpoint, groupsClaim string) GroupsVerifier {
+	return GroupsVerifier{
+		userinfoEndpoint: userinfoEndpoint,
+		groupsClaim:      groupsClaim,
+	}
+}
+
+// Groups fetches the userinfo endpoint with httpClient (which already
+// carries the user's access token) and returns the string slice found at
+// groupsClaim, if any.
+func (verifier GroupsVerifier) Groups(httpClient *http.Client) ([]string, error) {
+	resp, err := httpClient.Get(verifier.userinfoEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch userinfo: %s", err)
+	}
+	defer resp.Body.Close()
+
+	var userinfo map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&userinfo); err != nil {
+		return nil, fmt.Errorf("failed to decode userinfo: %s", err)
+	}
+
+	raw, ok := userinfo[verifier.groupsClaim].([]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	groups := make([]string, 0, len(raw))
+	for _, g := range raw {
+		if group, ok := g.(string); ok {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups, nil
+}
+
+func (verifier GroupsVerifier) Verify(httpClient *http.Client) (bool, error) {
+	groups, err := verifier.Groups(httpClient)
+	if err != nil {
+		return false, err
+	}
+
+	return len(groups) > 0, nil
+}
@@ -0,0 +1,73 @@
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+
+	"github.com/concourse/atc/auth/verifier"
+)
+
+const ProviderName = "oidc"
+
+// Provider is a generic OIDC provider, configured against any issuer that
+// publishes the standard /.well-known/openid-configuration discovery
+// document. It plugs into auth.OAuthRoutes the same way the GitHub
+// provider does.
+type Provider struct {
+	*oauth2.Config
+	verifier.Verifier
+}
+
+// NewProvider discovers the issuer's authorization, token and userinfo
+// endpoints and returns a Provider that lets a user log in if the
+// issuer's userinfo response carries a non-empty groupsClaim. The groups
+// themselves are not mapped to teams or roles, so any non-empty groups
+// claim grants the same access as every other authenticated user.
+func NewProvider(issuer, clientID, clientSecret, groupsClaim, redirectURL string) (Provider, error) {
+	discovery, err := discover(issuer)
+	if err != nil {
+		return Provider{}, fmt.Errorf("failed to discover oidc issuer %s: %s", issuer, err)
+	}
+
+	return Provider{
+		Config: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  discovery.AuthorizationEndpoint,
+				TokenURL: discovery.TokenEndpoint,
+			},
+			Scopes:      []string{"openid", "profile", "email", "groups"},
+			RedirectURL: redirectURL,
+		},
+		Verifier: NewGroupsVerifier(discovery.UserinfoEndpoint, groupsClaim),
+	}, nil
+}
+
+type discoveryDocument struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+func discover(issuer string) (discoveryDocument, error) {
+	resp, err := http.Get(issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return discoveryDocument{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return discoveryDocument{}, fmt.Errorf("unexpected status from discovery endpoint: %s", resp.Status)
+	}
+
+	var doc discoveryDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return discoveryDocument{}, fmt.Errorf("failed to decode discovery document: %s", err)
+	}
+
+	return doc, nil
+}
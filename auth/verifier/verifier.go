@@ -0,0 +1,30 @@
+package verifier
+
+import "net/http"
+
+// Verifier checks, after a successful OAuth exchange, whether the
+// authenticated user is actually allowed in (member of the configured
+// GitHub org, GitLab group, OIDC groups claim, ...). httpClient is an
+// *http.Client already carrying the user's access token.
+type Verifier interface {
+	Verify(httpClient *http.Client) (bool, error)
+}
+
+// Basket runs every Verifier in order and succeeds if any of them does,
+// mirroring auth.ValidatorBasket.
+type Basket []Verifier
+
+func (basket Basket) Verify(httpClient *http.Client) (bool, error) {
+	for _, v := range basket {
+		verified, err := v.Verify(httpClient)
+		if err != nil {
+			return false, err
+		}
+
+		if verified {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
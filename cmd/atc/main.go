@@ -2,6 +2,7 @@ package main
 
 import (
 	"crypto/rsa"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -30,23 +31,32 @@ import (
 	"github.com/tedsuo/ifrit/sigmon"
 	"github.com/tedsuo/rata"
 	"github.com/xoebus/zest"
+	"golang.org/x/net/http2"
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/api"
 	"github.com/concourse/atc/api/buildserver"
 	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/auth/bitbucket"
 	"github.com/concourse/atc/auth/github"
+	"github.com/concourse/atc/auth/gitlab"
+	"github.com/concourse/atc/auth/ldap"
+	"github.com/concourse/atc/auth/oidc"
 	"github.com/concourse/atc/builds"
 	"github.com/concourse/atc/config"
+	"github.com/concourse/atc/creds"
 	Db "github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/encryption"
 	"github.com/concourse/atc/db/migrations"
 	"github.com/concourse/atc/engine"
 	"github.com/concourse/atc/exec"
+	"github.com/concourse/atc/gc"
 	"github.com/concourse/atc/metric"
 	"github.com/concourse/atc/pipelines"
 	rdr "github.com/concourse/atc/radar"
 	"github.com/concourse/atc/resource"
 	sched "github.com/concourse/atc/scheduler"
+	"github.com/concourse/atc/syslog"
 	"github.com/concourse/atc/web/webhandler"
 	"github.com/concourse/atc/worker"
 	"github.com/concourse/atc/wrappa"
@@ -128,6 +138,24 @@ var webListenPort = flag.Int(
 	"port for the web server to listen on",
 )
 
+var tlsBindPort = flag.Int(
+	"tlsBindPort",
+	0,
+	"port for the web server to listen on with TLS; enables TLS termination when set along with -tlsCert and -tlsKey",
+)
+
+var tlsCert = flag.String(
+	"tlsCert",
+	"",
+	"path to a PEM-encoded certificate (or certificate chain) to use for TLS termination",
+)
+
+var tlsKey = flag.String(
+	"tlsKey",
+	"",
+	"path to a PEM-encoded private key to use for TLS termination",
+)
+
 var callbacksURLString = flag.String(
 	"callbacksURL",
 	"http://127.0.0.1:8080",
@@ -182,6 +210,132 @@ var gitHubAuthOrg = flag.String(
 	"name of github organization a user must be a member of to be authenticated",
 )
 
+var oidcIssuer = flag.String(
+	"oidcIssuer",
+	"",
+	"URL of the OIDC issuer to use for enabling OIDC auth, e.g. https://accounts.example.com (enables the oidc provider)",
+)
+
+var oidcClientID = flag.String(
+	"oidcClientID",
+	"",
+	"client ID to use for enabling OIDC auth",
+)
+
+var oidcClientSecret = flag.String(
+	"oidcClientSecret",
+	"",
+	"client secret to use for enabling OIDC auth",
+)
+
+var oidcGroupsClaim = flag.String(
+	"oidcGroupsClaim",
+	"groups",
+	"name of the userinfo claim containing the user's groups; a user may log in if it is non-empty (groups are not mapped to teams or roles)",
+)
+
+var gitLabHost = flag.String(
+	"gitLabHost",
+	"",
+	"address of a self-hosted GitLab server; defaults to https://gitlab.com",
+)
+
+var gitLabAuthGroup = flag.String(
+	"gitLabAuthGroup",
+	"",
+	"name of the gitlab group a user must be a member of to be authenticated (enables gitlab auth)",
+)
+
+var gitLabAuthClientID = flag.String(
+	"gitLabAuthClientID",
+	"",
+	"client ID to use for enabling gitlab auth",
+)
+
+var gitLabAuthClientSecret = flag.String(
+	"gitLabAuthClientSecret",
+	"",
+	"client secret to use for enabling gitlab auth",
+)
+
+var bitbucketAuthTeam = flag.String(
+	"bitbucketAuthTeam",
+	"",
+	"name of the bitbucket cloud workspace a user must be a member of to be authenticated (enables bitbucket auth)",
+)
+
+var bitbucketServerHost = flag.String(
+	"bitbucketServerHost",
+	"",
+	"address of a self-hosted BitBucket Server instance; if set, auth is done against it instead of BitBucket Cloud",
+)
+
+var bitbucketServerProject = flag.String(
+	"bitbucketServerProject",
+	"",
+	"key of the bitbucket server project a user must have access to; required when -bitbucketServerHost is set",
+)
+
+var bitbucketAuthClientID = flag.String(
+	"bitbucketAuthClientID",
+	"",
+	"client ID to use for enabling bitbucket auth",
+)
+
+var bitbucketAuthClientSecret = flag.String(
+	"bitbucketAuthClientSecret",
+	"",
+	"client secret to use for enabling bitbucket auth",
+)
+
+var ldapHost = flag.String(
+	"ldapHost",
+	"",
+	"address of the ldap/active directory server (enables ldap auth)",
+)
+
+var ldapPort = flag.Int(
+	"ldapPort",
+	636,
+	"port of the ldap/active directory server",
+)
+
+var ldapInsecure = flag.Bool(
+	"ldapInsecure",
+	false,
+	"connect to the ldap server over plain tcp instead of TLS (not recommended)",
+)
+
+var ldapBindDN = flag.String(
+	"ldapBindDN",
+	"",
+	"DN to bind as when searching for a user's DN; leave empty to bind anonymously",
+)
+
+var ldapBindPassword = flag.String(
+	"ldapBindPassword",
+	"",
+	"password for -ldapBindDN",
+)
+
+var ldapUserSearchBase = flag.String(
+	"ldapUserSearchBase",
+	"",
+	"base DN to search for a user's DN under",
+)
+
+var ldapUserSearchFilter = flag.String(
+	"ldapUserSearchFilter",
+	"(uid=%s)",
+	"filter used to search for a user's DN, with %s replaced by the submitted username",
+)
+
+var ldapGroupSearchBase = flag.String(
+	"ldapGroupSearchBase",
+	"",
+	"base DN to search for a user's group membership under",
+)
+
 var checkInterval = flag.Duration(
 	"checkInterval",
 	1*time.Minute,
@@ -254,12 +408,210 @@ var riemannAttributes = flag.String(
 	"Comma-separated list of key-value pairs to attach to all emitted metrics, e.g. a=b,c=d.",
 )
 
+var prometheusBindIP = flag.String(
+	"prometheusBindIP",
+	"",
+	"IP to listen on for a dedicated Prometheus /metrics listener; leave empty to mount /metrics on the debug listener instead",
+)
+
+var prometheusBindPort = flag.Int(
+	"prometheusBindPort",
+	9391,
+	"port for the dedicated Prometheus listener, when -prometheusBindIP is set",
+)
+
+var gcInterval = flag.Duration(
+	"gcInterval",
+	30*time.Second,
+	"interval on which to run each garbage collector",
+)
+
+var gcOneOffGracePeriod = flag.Duration(
+	"gcOneOffGracePeriod",
+	5*time.Minute,
+	"grace period before reaping the containers of a finished one-off build",
+)
+
+var gcMissingGracePeriod = flag.Duration(
+	"gcMissingGracePeriod",
+	5*time.Minute,
+	"grace period before reaping a container/volume the DB has no record requesting",
+)
+
+var syslogHostname = flag.String(
+	"syslogHostname",
+	"",
+	"hostname to send to the syslog/fluentd drain as the HOSTNAME field; defaults to the machine's hostname",
+)
+
+var syslogAddress = flag.String(
+	"syslogAddress",
+	"",
+	"address (host:port) of a remote syslog or fluentd endpoint to drain build event logs to (enables the drain)",
+)
+
+var syslogTransport = flag.String(
+	"syslogTransport",
+	"tcp",
+	"transport to use to connect to -syslogAddress: tcp, udp, or tls",
+)
+
+var syslogCACert = flag.String(
+	"syslogCACert",
+	"",
+	"path to a PEM-encoded CA cert file to use to verify the syslog/fluentd server, when -syslogTransport is tls",
+)
+
+var syslogDrainInterval = flag.Duration(
+	"syslogDrainInterval",
+	30*time.Second,
+	"interval on which to ship new build event log lines to -syslogAddress",
+)
+
+var syslogFluentd = flag.Bool(
+	"syslogFluentd",
+	false,
+	"speak the fluentd forward protocol to -syslogAddress instead of RFC 5424 syslog",
+)
+
 var sessionSigningKeyFile = flag.String(
 	"sessionSigningKeyFile",
 	"",
 	"file containing an RSA private key to use when signing session",
 )
 
+var vaultURL = flag.String(
+	"vault-url",
+	"",
+	"address of the vault server, e.g. https://vault.example.com:8200 (enables the vault credential manager)",
+)
+
+var vaultPathPrefix = flag.String(
+	"vault-path-prefix",
+	"/concourse",
+	"path under which to namespace vault secrets",
+)
+
+var vaultAuthBackend = flag.String(
+	"vault-auth-backend",
+	"",
+	"the dispatch path of the auth backend to use for authenticating with vault, e.g. 'approle'",
+)
+
+var vaultClientToken = flag.String(
+	"vault-client-token",
+	"",
+	"vault client token to use as an alternative to automatic approle authentication",
+)
+
+var vaultAuthApproleID = flag.String(
+	"vault-auth-approle-id",
+	"",
+	"approle role_id to authenticate with vault",
+)
+
+var vaultAuthApproleSecretID = flag.String(
+	"vault-auth-approle-secret-id",
+	"",
+	"approle secret_id to authenticate with vault",
+)
+
+var vaultCACert = flag.String(
+	"vault-ca-cert",
+	"",
+	"path to a PEM-encoded CA cert file to use to verify the vault server SSL cert",
+)
+
+var vaultInsecureSkipVerify = flag.Bool(
+	"vault-insecure-skip-verify",
+	false,
+	"skip verification of vault server SSL cert (not recommended)",
+)
+
+var credhubURL = flag.String(
+	"credhub-url",
+	"",
+	"address of the credhub server, e.g. https://credhub.example.com:8844 (enables the credhub credential manager)",
+)
+
+var credhubClientID = flag.String(
+	"credhub-client-id",
+	"",
+	"uaa client id to authenticate with credhub",
+)
+
+var credhubClientSecret = flag.String(
+	"credhub-client-secret",
+	"",
+	"uaa client secret to authenticate with credhub",
+)
+
+var credhubCACert = flag.String(
+	"credhub-ca-cert",
+	"",
+	"path to a PEM-encoded CA cert file to use to verify the credhub server SSL cert",
+)
+
+var credhubInsecureSkipVerify = flag.Bool(
+	"credhub-insecure-skip-verify",
+	false,
+	"skip verification of credhub server SSL cert (not recommended)",
+)
+
+var awsSecretsManagerRegion = flag.String(
+	"aws-secretsmanager-region",
+	"",
+	"AWS region to use for the AWS Secrets Manager credential manager (enables it when set)",
+)
+
+var awsSecretsManagerAccessKey = flag.String(
+	"aws-secretsmanager-access-key",
+	"",
+	"AWS access key to use for the AWS Secrets Manager credential manager, in place of the default credential chain",
+)
+
+var awsSecretsManagerSecretKey = flag.String(
+	"aws-secretsmanager-secret-key",
+	"",
+	"AWS secret key to use for the AWS Secrets Manager credential manager, in place of the default credential chain",
+)
+
+var awsSecretsManagerSessionToken = flag.String(
+	"aws-secretsmanager-session-token",
+	"",
+	"AWS session token to use for the AWS Secrets Manager credential manager",
+)
+
+var kubernetesSecretsEnabled = flag.Bool(
+	"kubernetes-secrets",
+	false,
+	"enable the kubernetes credential manager, reading Secrets from the cluster ATC is running in",
+)
+
+var kubernetesKubeconfigPath = flag.String(
+	"kubernetes-kubeconfig",
+	"",
+	"path to a kubeconfig file to use instead of the in-cluster service account",
+)
+
+var kubernetesNamespacePrefix = flag.String(
+	"kubernetes-namespace-prefix",
+	"concourse-",
+	"prefix prepended to a team name to determine its secrets namespace",
+)
+
+var encryptionKeyFile = flag.String(
+	"encryptionKey",
+	"",
+	"file containing 16, 24, or 32 bytes to use as the AES-GCM key for encrypting sensitive database columns at rest",
+)
+
+var oldEncryptionKeyFile = flag.String(
+	"oldEncryptionKey",
+	"",
+	"file containing the previous AES-GCM key, to decrypt rows while rotating to -encryptionKey",
+)
+
 func main() {
 	flag.Parse()
 
@@ -275,6 +627,43 @@ func main() {
 		fatal(errors.New("must specify -sessionSigningKeyFile if -gitHubAuthClientID and -gitHubAuthClientSecret are given"))
 	}
 
+	if *oldEncryptionKeyFile != "" && *encryptionKeyFile == "" {
+		fatal(errors.New("must specify -encryptionKey to rotate from -oldEncryptionKey"))
+	}
+
+	if (*oidcIssuer != "" || *gitLabAuthGroup != "" || *bitbucketAuthTeam != "") && *externalURL == "" {
+		fatal(errors.New("must specify -externalURL if -oidcIssuer, -gitLabAuthGroup, or -bitbucketAuthTeam are given"))
+	}
+
+	if *bitbucketServerHost != "" && *bitbucketServerProject == "" {
+		fatal(errors.New("must specify -bitbucketServerProject if -bitbucketServerHost is given"))
+	}
+
+	tlsEnabled := *tlsBindPort != 0
+
+	if tlsEnabled && (*tlsCert == "" || *tlsKey == "") {
+		fatal(errors.New("must specify -tlsCert and -tlsKey if -tlsBindPort is given"))
+	}
+
+	if !tlsEnabled && (*tlsCert != "" || *tlsKey != "") {
+		fatal(errors.New("must specify -tlsBindPort if -tlsCert and -tlsKey are given"))
+	}
+
+	if *externalURL != "" {
+		externalURLParsed, err := url.Parse(*externalURL)
+		if err != nil {
+			fatal(fmt.Errorf("invalid -externalURL: %s", err))
+		}
+
+		if tlsEnabled && externalURLParsed.Scheme != "https" {
+			fatal(errors.New("must specify an https:// -externalURL when TLS is enabled, so OAuth callbacks don't silently break"))
+		}
+
+		if !tlsEnabled && externalURLParsed.Scheme == "https" {
+			fatal(errors.New("-externalURL is https:// but TLS is not enabled via -tlsBindPort"))
+		}
+	}
+
 	if _, err := os.Stat(*templatesDir); err != nil {
 		fatal(errors.New("directory specified via -templates does not exist"))
 	}
@@ -298,22 +687,91 @@ func main() {
 		logger.RegisterSink(yellerSink)
 	}
 
+	var metricsEmitters []metric.Emitter
+
 	if *riemannAddr != "" {
 		host := *riemannHost
 		if host == "" {
 			host, _ = os.Hostname()
 		}
 
-		metric.Initialize(
-			logger.Session("metrics"),
+		riemannEmitter, err := metric.NewRiemannEmitter(
 			*riemannAddr,
 			host,
 			strings.Split(*riemannTags, ","),
 			parseAttributes(logger, *riemannAttributes),
 		)
+		if err != nil {
+			fatal(err)
+		}
+
+		metricsEmitters = append(metricsEmitters, riemannEmitter)
 	}
 
-	var err error
+	prometheusEmitter := metric.NewPrometheusEmitter()
+
+	if *prometheusBindIP != "" {
+		if err := prometheusEmitter.Listen(*prometheusBindIP, *prometheusBindPort); err != nil {
+			fatal(err)
+		}
+	} else {
+		// mounted on the debug listener's mux below, alongside pprof
+		prometheusEmitter.Mount(http.DefaultServeMux)
+	}
+
+	metricsEmitters = append(metricsEmitters, prometheusEmitter)
+
+	metric.Initialize(logger.Session("metrics"), metricsEmitters)
+
+	credsManagers := creds.Managers{
+		"vault": &creds.VaultManager{
+			URL:           *vaultURL,
+			PathPrefix:    *vaultPathPrefix,
+			AuthBackend:   *vaultAuthBackend,
+			ClientToken:   *vaultClientToken,
+			ApproleID:     *vaultAuthApproleID,
+			ApproleSecret: *vaultAuthApproleSecretID,
+			CACert:        *vaultCACert,
+			Insecure:      *vaultInsecureSkipVerify,
+		},
+		"credhub": &creds.CredHubManager{
+			URL:          *credhubURL,
+			ClientID:     *credhubClientID,
+			ClientSecret: *credhubClientSecret,
+			CACert:       *credhubCACert,
+			Insecure:     *credhubInsecureSkipVerify,
+		},
+		"aws-secretsmanager": &creds.AWSSecretsManager{
+			Region:       *awsSecretsManagerRegion,
+			AccessKey:    *awsSecretsManagerAccessKey,
+			SecretKey:    *awsSecretsManagerSecretKey,
+			SessionToken: *awsSecretsManagerSessionToken,
+		},
+		"kubernetes": &creds.KubernetesManager{
+			Enabled:         *kubernetesSecretsEnabled,
+			KubeconfigPath:  *kubernetesKubeconfigPath,
+			NamespacePrefix: *kubernetesNamespacePrefix,
+		},
+	}
+
+	credsManager, err := credsManagers.Configured()
+	if err != nil {
+		fatal(err)
+	}
+
+	if credsManager == nil {
+		credsManager = creds.NoopManager{}
+	}
+
+	secretsFactory, err := credsManager.NewSecretsFactory(logger.Session("creds"))
+	if err != nil {
+		fatal(err)
+	}
+
+	encryptionStrategy, oldEncryptionStrategy, err := constructEncryptionStrategy()
+	if err != nil {
+		fatal(err)
+	}
 
 	var dbConn Db.Conn
 	dbConn, err = migrations.LockDBAndMigrate(logger.Session("db.migrations"), *sqlDriver, *sqlDataSource)
@@ -325,7 +783,7 @@ func main() {
 	bus := Db.NewNotificationsBus(listener, dbConn)
 
 	explainDBConn := Db.Explain(logger, dbConn, clock.NewClock(), 500*time.Millisecond)
-	db := Db.NewSQL(logger.Session("db"), explainDBConn, bus)
+	db := Db.NewSQL(logger.Session("db"), explainDBConn, bus, encryptionStrategy)
 	pipelineDBFactory := Db.NewPipelineDBFactory(logger.Session("db"), explainDBConn, bus, db)
 
 	var configDB Db.ConfigDB
@@ -359,7 +817,7 @@ func main() {
 		return guid.String()
 	})
 
-	execEngine := engine.NewExecEngine(gardenFactory, engine.NewBuildDelegateFactory(db), db)
+	execEngine := engine.NewExecEngine(gardenFactory, engine.NewBuildDelegateFactory(db, secretsFactory), db)
 
 	engine := engine.NewDBEngine(engine.Engines{execEngine}, db)
 
@@ -377,7 +835,7 @@ func main() {
 		}
 	}
 
-	validator, basicAuthEnabled := constructValidator(signingKey)
+	validator, basicAuthEnabled := constructValidator(logger, signingKey)
 
 	oauthProviders := auth.Providers{}
 
@@ -397,6 +855,71 @@ func main() {
 		)
 	}
 
+	if *oidcIssuer != "" {
+		path, err := auth.OAuthRoutes.CreatePathForRoute(auth.OAuthCallback, rata.Params{
+			"provider": oidc.ProviderName,
+		})
+		if err != nil {
+			fatal(err)
+		}
+
+		oidcProvider, err := oidc.NewProvider(
+			*oidcIssuer,
+			*oidcClientID,
+			*oidcClientSecret,
+			*oidcGroupsClaim,
+			*externalURL+path,
+		)
+		if err != nil {
+			fatal(err)
+		}
+
+		oauthProviders[oidc.ProviderName] = oidcProvider
+	}
+
+	if *gitLabAuthGroup != "" {
+		path, err := auth.OAuthRoutes.CreatePathForRoute(auth.OAuthCallback, rata.Params{
+			"provider": gitlab.ProviderName,
+		})
+		if err != nil {
+			fatal(err)
+		}
+
+		oauthProviders[gitlab.ProviderName] = gitlab.NewProvider(
+			*gitLabHost,
+			*gitLabAuthGroup,
+			*gitLabAuthClientID,
+			*gitLabAuthClientSecret,
+			*externalURL+path,
+		)
+	}
+
+	if *bitbucketAuthTeam != "" {
+		path, err := auth.OAuthRoutes.CreatePathForRoute(auth.OAuthCallback, rata.Params{
+			"provider": bitbucket.ProviderName,
+		})
+		if err != nil {
+			fatal(err)
+		}
+
+		if *bitbucketServerHost != "" {
+			oauthProviders[bitbucket.ProviderName] = bitbucket.NewServerProvider(
+				*bitbucketServerHost,
+				*bitbucketServerProject,
+				*bitbucketAuthClientID,
+				*bitbucketAuthClientSecret,
+				*externalURL+path,
+			)
+		} else {
+			oauthProviders[bitbucket.ProviderName] = bitbucket.NewProvider(
+				*bitbucketAuthTeam,
+				*bitbucketAuthClientID,
+				*bitbucketAuthClientSecret,
+				*externalURL+path,
+			)
+		}
+	}
+
 	callbacksURL, err := url.Parse(*callbacksURLString)
 	if err != nil {
 		fatal(err)
@@ -479,6 +1002,7 @@ func main() {
 
 	httpHandler = auth.CookieSetHandler{
 		Handler: httpHandler,
+		Secure:  tlsEnabled,
 	}
 
 	httpHandler = httpmetrics.Wrap(httpHandler)
@@ -489,6 +1013,12 @@ func main() {
 	webListenAddr := fmt.Sprintf("%s:%d", *webListenAddress, *webListenPort)
 	debugListenAddr := fmt.Sprintf("%s:%d", *debugListenAddress, *debugListenPort)
 
+	plainHTTPHandler := httpHandler
+
+	if tlsEnabled {
+		plainHTTPHandler = redirectToHTTPSHandler{externalURL: *externalURL}
+	}
+
 	syncer := pipelines.NewSyncer(
 		logger.Session("syncer"),
 		db,
@@ -530,7 +1060,7 @@ func main() {
 	)
 
 	memberGrouper := []grouper.Member{
-		{"web", http_server.New(webListenAddr, httpHandler)},
+		{"web", http_server.New(webListenAddr, plainHTTPHandler)},
 
 		{"debug", http_server.New(debugListenAddr, http.DefaultServeMux)},
 
@@ -557,6 +1087,155 @@ func main() {
 		}},
 	}
 
+	for _, collector := range []struct {
+		name      string
+		collector gc.Collector
+	}{
+		{"container-collector", gc.ContainerCollector{
+			Logger:             logger.Session("container-collector"),
+			ContainerDB:        db,
+			Workers:            workerClient,
+			OneOffGracePeriod:  *gcOneOffGracePeriod,
+			MissingGracePeriod: *gcMissingGracePeriod,
+		}},
+		{"volume-collector", gc.VolumeCollector{
+			Logger:   logger.Session("volume-collector"),
+			VolumeDB: db,
+			Workers:  workerClient,
+		}},
+		{"build-event-collector", gc.BuildEventCollector{
+			Logger:       logger.Session("build-event-collector"),
+			BuildEventDB: db,
+		}},
+		{"resource-cache-collector", gc.ResourceCacheCollector{
+			Logger:          logger.Session("resource-cache-collector"),
+			ResourceCacheDB: db,
+		}},
+		{"worker-collector", gc.WorkerCollector{
+			Logger:   logger.Session("worker-collector"),
+			WorkerDB: db,
+		}},
+	} {
+		memberGrouper = append(memberGrouper,
+			grouper.Member{
+				Name: collector.name,
+				Runner: gc.Runner{
+					Logger:    logger.Session(collector.name),
+					Collector: collector.collector,
+					Locks:     db,
+					LockName:  collector.name,
+					Interval:  *gcInterval,
+					Clock:     clock.NewClock(),
+				},
+			},
+		)
+	}
+
+	// drain build event logs to a long-term syslog/fluentd endpoint
+	if *syslogAddress != "" {
+		hostname := *syslogHostname
+		if hostname == "" {
+			hostname, _ = os.Hostname()
+		}
+
+		syslogConfig := syslog.Config{
+			Hostname:      hostname,
+			Address:       *syslogAddress,
+			Transport:     syslog.Transport(*syslogTransport),
+			CACert:        *syslogCACert,
+			DrainInterval: *syslogDrainInterval,
+		}
+
+		newWriter := syslog.NewRFC5424Writer
+		if *syslogFluentd {
+			newWriter = syslog.NewFluentdWriter
+		}
+
+		memberGrouper = append(memberGrouper,
+			grouper.Member{
+				Name: "syslog-drainer",
+				Runner: syslog.Runner{
+					Drainer: syslog.Drainer{
+						Logger:  logger.Session("syslog-drainer"),
+						Cursors: db,
+						Events:  db,
+						NewWriter: func() (syslog.Writer, error) {
+							return newWriter(syslogConfig)
+						},
+						Config: syslogConfig,
+					},
+					Interval: *syslogDrainInterval,
+					Clock:    clock.NewClock(),
+				},
+			},
+		)
+	}
+
+	// rotate rows encrypted under -oldEncryptionKey to -encryptionKey
+	if oldEncryptionStrategy != nil {
+		memberGrouper = append(memberGrouper,
+			grouper.Member{
+				Name: "encryption-key-rotator",
+				Runner: encryption.Rotator{
+					Logger: logger.Session("encryption-key-rotator"),
+					OldKey: oldEncryptionStrategy,
+					NewKey: encryptionStrategy,
+					Tables: db.EncryptedTables(),
+				},
+			},
+		)
+	}
+
+	if tlsEnabled {
+		tlsListenAddr := fmt.Sprintf("%s:%d", *webListenAddress, *tlsBindPort)
+
+		cert, err := tls.LoadX509KeyPair(*tlsCert, *tlsKey)
+		if err != nil {
+			fatal(fmt.Errorf("failed to load -tlsCert/-tlsKey: %s", err))
+		}
+
+		tlsConfig := &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			NextProtos:   []string{"h2"},
+		}
+
+		tlsServer := &http.Server{
+			Addr:      tlsListenAddr,
+			Handler:   httpHandler,
+			TLSConfig: tlsConfig,
+		}
+
+		if err := http2.ConfigureServer(tlsServer, nil); err != nil {
+			fatal(fmt.Errorf("failed to configure http2: %s", err))
+		}
+
+		memberGrouper = append(memberGrouper,
+			grouper.Member{
+				Name: "web-tls",
+				Runner: ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+					listener, err := tls.Listen("tcp", tlsListenAddr, tlsConfig)
+					if err != nil {
+						return fmt.Errorf("failed to listen for tls: %s", err)
+					}
+
+					serveErr := make(chan error, 1)
+					go func() {
+						serveErr <- tlsServer.Serve(listener)
+					}()
+
+					close(ready)
+
+					select {
+					case <-signals:
+						return listener.Close()
+					case err := <-serveErr:
+						return fmt.Errorf("tls server exited: %s", err)
+					}
+				}),
+			},
+		)
+	}
+
 	// register a hardcoded worker
 	if *gardenAddr != "" {
 		memberGrouper = append(memberGrouper,
@@ -577,6 +1256,7 @@ func main() {
 	logger.Info("listening", lager.Data{
 		"web":   webListenAddr,
 		"debug": debugListenAddr,
+		"tls":   tlsEnabled,
 	})
 
 	err = <-running.Wait()
@@ -586,12 +1266,33 @@ func main() {
 	}
 }
 
+// redirectToHTTPSHandler is what the plain HTTP listener serves once TLS
+// is enabled, so links and bookmarks to the old http:// address still
+// work.
+type redirectToHTTPSHandler struct {
+	externalURL string
+}
+
+func (handler redirectToHTTPSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u := *r.URL
+	u.Scheme = "https"
+	u.Host = r.Host
+
+	if handler.externalURL != "" {
+		if external, err := url.Parse(handler.externalURL); err == nil {
+			u.Host = external.Host
+		}
+	}
+
+	http.Redirect(w, r, u.String(), http.StatusMovedPermanently)
+}
+
 func fatal(err error) {
 	println(err.Error())
 	os.Exit(1)
 }
 
-func constructValidator(signingKey *rsa.PrivateKey) (auth.Validator, bool) {
+func constructValidator(logger lager.Logger, signingKey *rsa.PrivateKey) (auth.Validator, bool) {
 	if *dev {
 		return auth.NoopValidator{}, false
 	}
@@ -610,6 +1311,22 @@ func constructValidator(signingKey *rsa.PrivateKey) (auth.Validator, bool) {
 		}
 	}
 
+	if *ldapHost != "" {
+		basicAuthValidator = ldap.Validator{
+			Logger: logger.Session("ldap"),
+			Config: ldap.Config{
+				Host:             *ldapHost,
+				Port:             *ldapPort,
+				Insecure:         *ldapInsecure,
+				BindDN:           *ldapBindDN,
+				BindPassword:     *ldapBindPassword,
+				UserSearchBase:   *ldapUserSearchBase,
+				UserSearchFilter: *ldapUserSearchFilter,
+				GroupSearchBase:  *ldapGroupSearchBase,
+			},
+		}
+	}
+
 	var jwtValidator auth.Validator
 
 	if signingKey != nil {
@@ -633,6 +1350,36 @@ func constructValidator(signingKey *rsa.PrivateKey) (auth.Validator, bool) {
 	return validator, basicAuthValidator != nil
 }
 
+func constructEncryptionStrategy() (encryption.Strategy, encryption.Strategy, error) {
+	if *encryptionKeyFile == "" {
+		return encryption.NoEncryption{}, nil, nil
+	}
+
+	key, err := readEncryptionKey(*encryptionKeyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read -encryptionKey: %s", err)
+	}
+
+	var oldKey encryption.Strategy
+	if *oldEncryptionKeyFile != "" {
+		oldKey, err = readEncryptionKey(*oldEncryptionKeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read -oldEncryptionKey: %s", err)
+		}
+	}
+
+	return key, oldKey, nil
+}
+
+func readEncryptionKey(path string) (*encryption.Key, error) {
+	keyBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryption.NewKey(keyBytes)
+}
+
 func parseAttributes(logger lager.Logger, pairs string) map[string]string {
 	attributes := map[string]string{}
 	for _, pair := range strings.Split(*riemannAttributes, ",") {
@@ -0,0 +1,97 @@
+package creds
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/pivotal-golang/lager"
+)
+
+// AWSSecretsManager configures access to AWS Secrets Manager. When both
+// AccessKey and SecretKey are set, those static keys (with SessionToken, if
+// any) are used; otherwise credentials are resolved through the usual AWS
+// SDK chain (env vars, shared config, instance profile).
+type AWSSecretsManager struct {
+	Region string
+
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+func (manager *AWSSecretsManager) Name() string { return "aws-secretsmanager" }
+
+func (manager *AWSSecretsManager) IsConfigured() bool {
+	return manager.Region != ""
+}
+
+func (manager *AWSSecretsManager) Validate() error {
+	return nil
+}
+
+func (manager *AWSSecretsManager) NewSecretsFactory(logger lager.Logger) (SecretsFactory, error) {
+	config := aws.NewConfig().WithRegion(manager.Region)
+
+	if manager.AccessKey != "" && manager.SecretKey != "" {
+		config = config.WithCredentials(
+			credentialsFromStaticKeys(manager.AccessKey, manager.SecretKey, manager.SessionToken),
+		)
+	}
+
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct aws session: %s", err)
+	}
+
+	return &asmSecretsFactory{
+		logger: logger,
+		client: secretsmanager.New(sess),
+	}, nil
+}
+
+type asmSecretsFactory struct {
+	logger lager.Logger
+	client *secretsmanager.SecretsManager
+}
+
+func (factory *asmSecretsFactory) NewSecrets(team, pipeline string) Secrets {
+	return &asmSecrets{
+		client:   factory.client,
+		team:     team,
+		pipeline: pipeline,
+	}
+}
+
+type asmSecrets struct {
+	client   *secretsmanager.SecretsManager
+	team     string
+	pipeline string
+}
+
+func (secrets *asmSecrets) Get(key string) (interface{}, bool, error) {
+	for _, path := range LookupPaths(secrets.team, secrets.pipeline, key) {
+		path = DefaultPathPrefix + path
+
+		out, err := secrets.client.GetSecretValue(&secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(path),
+		})
+		if err != nil {
+			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == secretsmanager.ErrCodeResourceNotFoundException {
+				continue
+			}
+
+			return nil, false, fmt.Errorf("failed to fetch %s from secretsmanager: %s", path, err)
+		}
+
+		if out.SecretString != nil {
+			return *out.SecretString, true, nil
+		}
+
+		return out.SecretBinary, true, nil
+	}
+
+	return nil, false, nil
+}
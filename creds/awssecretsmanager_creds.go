@@ -0,0 +1,7 @@
+package creds
+
+import "github.com/aws/aws-sdk-go/aws/credentials"
+
+func credentialsFromStaticKeys(accessKey, secretKey, sessionToken string) *credentials.Credentials {
+	return credentials.NewStaticCredentials(accessKey, secretKey, sessionToken)
+}
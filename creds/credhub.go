@@ -0,0 +1,102 @@
+package creds
+
+import (
+	"fmt"
+	"strings"
+
+	credhub "code.cloudfoundry.org/credhub-cli/credhub"
+	"github.com/pivotal-golang/lager"
+)
+
+// CredHubManager configures access to a CF CredHub server, authenticating
+// with a UAA client ID/secret as is typical for a CF-deployed CredHub.
+type CredHubManager struct {
+	URL string
+
+	CACert string
+
+	ClientID     string
+	ClientSecret string
+
+	Insecure bool
+}
+
+func (manager *CredHubManager) Name() string { return "credhub" }
+
+func (manager *CredHubManager) IsConfigured() bool {
+	return manager.URL != ""
+}
+
+func (manager *CredHubManager) Validate() error {
+	if manager.ClientID == "" || manager.ClientSecret == "" {
+		return fmt.Errorf("must configure -credhub-client-id and -credhub-client-secret")
+	}
+
+	return nil
+}
+
+func (manager *CredHubManager) NewSecretsFactory(logger lager.Logger) (SecretsFactory, error) {
+	options := []credhub.Option{
+		credhub.Auth(credhub.UaaClientCredentials(manager.ClientID, manager.ClientSecret)),
+	}
+
+	if manager.CACert != "" {
+		options = append(options, credhub.CaCerts(manager.CACert))
+	}
+
+	if manager.Insecure {
+		options = append(options, credhub.SkipTLSValidation(true))
+	}
+
+	ch, err := credhub.New(manager.URL, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct credhub client: %s", err)
+	}
+
+	return &credhubSecretsFactory{logger: logger, ch: ch}, nil
+}
+
+type credhubSecretsFactory struct {
+	logger lager.Logger
+	ch     *credhub.CredHub
+}
+
+func (factory *credhubSecretsFactory) NewSecrets(team, pipeline string) Secrets {
+	return &credhubSecrets{
+		ch:       factory.ch,
+		team:     team,
+		pipeline: pipeline,
+	}
+}
+
+type credhubSecrets struct {
+	ch       *credhub.CredHub
+	team     string
+	pipeline string
+}
+
+func (secrets *credhubSecrets) Get(key string) (interface{}, bool, error) {
+	for _, path := range LookupPaths(secrets.team, secrets.pipeline, key) {
+		fullPath := DefaultPathPrefix + path
+
+		cred, err := secrets.ch.GetLatestVersion(fullPath)
+		if err != nil {
+			if isCredhubNotFound(err) {
+				continue
+			}
+
+			return nil, false, fmt.Errorf("failed to fetch %s from credhub: %s", fullPath, err)
+		}
+
+		return cred.Value, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// isCredhubNotFound distinguishes "no credential at this path" from a
+// real failure (auth, network, permissions). The credhub-cli client
+// doesn't expose a typed not-found error, just the API's own message.
+func isCredhubNotFound(err error) bool {
+	return strings.Contains(err.Error(), "credential does not exist")
+}
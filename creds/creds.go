@@ -0,0 +1,100 @@
+package creds
+
+import (
+	"fmt"
+
+	"github.com/pivotal-golang/lager"
+)
+
+// Manager is implemented by each pluggable secret backend (Vault, CredHub,
+// AWS Secrets Manager, Kubernetes, ...). It is responsible for constructing
+// a Secrets lookup once at startup, after flags have been parsed and
+// validated.
+type Manager interface {
+	// Name identifies the backend, e.g. "vault", for logging and error
+	// messages.
+	Name() string
+
+	// IsConfigured returns true if enough flags were provided to enable
+	// this backend. Exactly zero or one configured Manager is expected;
+	// main.go treats more than one as a configuration error.
+	IsConfigured() bool
+
+	// Validate returns an error if the backend is configured but missing
+	// required flags (e.g. -vault-url without -vault-client-token and
+	// without AppRole credentials).
+	Validate() error
+
+	NewSecretsFactory(logger lager.Logger) (SecretsFactory, error)
+}
+
+// SecretsFactory builds a team- and pipeline-scoped Secrets lookup. A new
+// Secrets is built per build so that lease/TTL state doesn't leak between
+// unrelated builds.
+type SecretsFactory interface {
+	NewSecrets(team, pipeline string) Secrets
+}
+
+// Secrets resolves a single `((param))` token to its current value. The
+// bool return indicates whether the key was found at all, distinguishing
+// "not found" from "found but empty".
+type Secrets interface {
+	Get(path string) (interface{}, bool, error)
+}
+
+// Managers is the set of backends registered at startup, keyed by flag
+// prefix (e.g. "vault"). main.go walks this list after flag.Parse to
+// figure out which backend, if any, the operator configured.
+type Managers map[string]Manager
+
+func (managers Managers) Configured() (Manager, error) {
+	var configured Manager
+
+	for _, manager := range managers {
+		if !manager.IsConfigured() {
+			continue
+		}
+
+		if configured != nil {
+			return nil, fmt.Errorf(
+				"multiple credential managers configured: %s and %s",
+				configured.Name(),
+				manager.Name(),
+			)
+		}
+
+		if err := manager.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s configuration: %s", manager.Name(), err)
+		}
+
+		configured = manager
+	}
+
+	return configured, nil
+}
+
+// DefaultPathPrefix is the namespace backends without their own
+// configurable prefix (CredHub, AWS Secrets Manager) use ahead of the
+// paths returned by LookupPaths.
+const DefaultPathPrefix = "/concourse"
+
+// LookupPaths returns the ordered set of paths to try for a given key
+// under some backend-supplied prefix, scoped first to the pipeline and
+// falling back to the team. It does not include that prefix itself, so
+// callers combine it with their own (DefaultPathPrefix, or a configurable
+// one like VaultManager.PathPrefix) rather than getting it baked in
+// twice. e.g. for team "main", pipeline "foo", key "api-key" this yields:
+//
+//	/main/foo/api-key
+//	/main/api-key
+func LookupPaths(team string, pipeline string, key string) []string {
+	var paths []string
+
+	if pipeline != "" {
+		paths = append(paths, fmt.Sprintf("/%s/%s/%s", team, pipeline, key))
+	}
+
+	paths = append(paths, fmt.Sprintf("/%s/%s", team, key))
+
+	return paths
+}
@@ -0,0 +1,102 @@
+package creds
+
+import (
+	"fmt"
+
+	"github.com/pivotal-golang/lager"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// KubernetesManager reads secrets out of Kubernetes Secret objects in the
+// cluster ATC itself is running in, using the in-cluster service account
+// unless KubeconfigPath is set (useful when running ATC outside the
+// cluster during development).
+type KubernetesManager struct {
+	Enabled bool
+
+	KubeconfigPath string
+
+	NamespacePrefix string
+}
+
+func (manager *KubernetesManager) Name() string { return "kubernetes" }
+
+func (manager *KubernetesManager) IsConfigured() bool {
+	return manager.Enabled
+}
+
+func (manager *KubernetesManager) Validate() error {
+	return nil
+}
+
+func (manager *KubernetesManager) NewSecretsFactory(logger lager.Logger) (SecretsFactory, error) {
+	config, err := manager.restConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kubernetes client config: %s", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct kubernetes client: %s", err)
+	}
+
+	return &kubernetesSecretsFactory{
+		logger:          logger,
+		clientset:       clientset,
+		namespacePrefix: manager.NamespacePrefix,
+	}, nil
+}
+
+func (manager *KubernetesManager) restConfig() (*rest.Config, error) {
+	if manager.KubeconfigPath != "" {
+		return clientcmd.BuildConfigFromFlags("", manager.KubeconfigPath)
+	}
+
+	return rest.InClusterConfig()
+}
+
+type kubernetesSecretsFactory struct {
+	logger          lager.Logger
+	clientset       *kubernetes.Clientset
+	namespacePrefix string
+}
+
+func (factory *kubernetesSecretsFactory) NewSecrets(team, pipeline string) Secrets {
+	return &kubernetesSecrets{
+		clientset: factory.clientset,
+		namespace: factory.namespacePrefix + team,
+		pipeline:  pipeline,
+	}
+}
+
+type kubernetesSecrets struct {
+	clientset *kubernetes.Clientset
+	namespace string
+	pipeline  string
+}
+
+func (secrets *kubernetesSecrets) Get(key string) (interface{}, bool, error) {
+	secret, err := secrets.clientset.CoreV1().Secrets(secrets.namespace).Get(key, metaGetOptions())
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, false, nil
+		}
+
+		return nil, false, fmt.Errorf("failed to fetch secret %s/%s from kubernetes: %s", secrets.namespace, key, err)
+	}
+
+	if secrets.pipeline != "" {
+		if value, found := secret.Data[secrets.pipeline+"."+key]; found {
+			return string(value), true, nil
+		}
+	}
+
+	if value, found := secret.Data[key]; found {
+		return string(value), true, nil
+	}
+
+	return nil, false, nil
+}
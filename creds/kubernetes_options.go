@@ -0,0 +1,7 @@
+package creds
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+func metaGetOptions() metav1.GetOptions {
+	return metav1.GetOptions{}
+}
@@ -0,0 +1,169 @@
+package creds
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pivotal-golang/clock"
+	"github.com/pivotal-golang/lager"
+)
+
+// VaultManager configures access to a HashiCorp Vault cluster. Either
+// ClientToken or the AppRole pair must be set; PathPrefix defaults to
+// "/concourse" and is combined with LookupPaths to form the full Vault
+// path for a given team/pipeline/key.
+type VaultManager struct {
+	URL string
+
+	PathPrefix string
+
+	AuthBackend string
+
+	ClientToken string
+
+	ApproleID     string
+	ApproleSecret string
+
+	CACert     string
+	ClientCert string
+	ClientKey  string
+	ServerName string
+
+	Insecure bool
+}
+
+func (manager *VaultManager) Name() string { return "vault" }
+
+func (manager *VaultManager) IsConfigured() bool {
+	return manager.URL != ""
+}
+
+func (manager *VaultManager) Validate() error {
+	if manager.ClientToken == "" && (manager.ApproleID == "" || manager.ApproleSecret == "") {
+		return fmt.Errorf("must configure either -vault-client-token or -vault-auth-approle-id and -vault-auth-approle-secret-id")
+	}
+
+	return nil
+}
+
+func (manager *VaultManager) NewSecretsFactory(logger lager.Logger) (SecretsFactory, error) {
+	client, err := newVaultClient(manager)
+	if err != nil {
+		return nil, err
+	}
+
+	if manager.ClientToken != "" {
+		client.setToken(manager.ClientToken)
+	} else {
+		err := client.authenticateWithApprole(manager.AuthBackend, manager.ApproleID, manager.ApproleSecret)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &vaultSecretsFactory{
+		logger: logger,
+		client: client,
+		prefix: manager.PathPrefix,
+		clock:  clock.NewClock(),
+	}, nil
+}
+
+// vaultReader is the subset of vaultClient that vaultSecrets needs to read
+// a path, split out so tests can fake it without a real Vault server.
+type vaultReader interface {
+	read(path string) (interface{}, bool, time.Duration, error)
+}
+
+type vaultSecretsFactory struct {
+	logger lager.Logger
+	client vaultReader
+	prefix string
+	clock  clock.Clock
+}
+
+func (factory *vaultSecretsFactory) NewSecrets(team, pipeline string) Secrets {
+	return &vaultSecrets{
+		logger:   factory.logger.Session("vault"),
+		client:   factory.client,
+		prefix:   factory.prefix,
+		team:     team,
+		pipeline: pipeline,
+		clock:    factory.clock,
+		cache:    map[string]vaultCacheEntry{},
+	}
+}
+
+type vaultCacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// vaultSecrets caches lease-bearing responses for their reported TTL so a
+// build that reads the same secret many times doesn't hammer Vault.
+type vaultSecrets struct {
+	logger lager.Logger
+
+	client vaultReader
+
+	prefix   string
+	team     string
+	pipeline string
+
+	clock clock.Clock
+
+	mu    sync.Mutex
+	cache map[string]vaultCacheEntry
+}
+
+func (secrets *vaultSecrets) Get(key string) (interface{}, bool, error) {
+	for _, path := range LookupPaths(secrets.team, secrets.pipeline, key) {
+		fullPath := secrets.prefix + path
+
+		if value, found := secrets.cached(fullPath); found {
+			return value, true, nil
+		}
+
+		value, found, leaseDuration, err := secrets.client.read(fullPath)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if !found {
+			continue
+		}
+
+		secrets.cacheFor(fullPath, value, leaseDuration)
+
+		return value, true, nil
+	}
+
+	return nil, false, nil
+}
+
+func (secrets *vaultSecrets) cached(path string) (interface{}, bool) {
+	secrets.mu.Lock()
+	defer secrets.mu.Unlock()
+
+	entry, found := secrets.cache[path]
+	if !found || secrets.clock.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+
+	return entry.value, true
+}
+
+func (secrets *vaultSecrets) cacheFor(path string, value interface{}, leaseDuration time.Duration) {
+	if leaseDuration <= 0 {
+		return
+	}
+
+	secrets.mu.Lock()
+	defer secrets.mu.Unlock()
+
+	secrets.cache[path] = vaultCacheEntry{
+		value:     value,
+		expiresAt: secrets.clock.Now().Add(leaseDuration),
+	}
+}
@@ -0,0 +1,82 @@
+package creds
+
+import (
+	"fmt"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// vaultClient is a thin wrapper around the upstream Vault API client so the
+// rest of this package only has to deal with (value, found, leaseDuration,
+// error) instead of the raw *vaultapi.Secret shape.
+type vaultClient struct {
+	api *vaultapi.Client
+}
+
+func newVaultClient(manager *VaultManager) (*vaultClient, error) {
+	config := vaultapi.DefaultConfig()
+	config.Address = manager.URL
+
+	err := config.ConfigureTLS(&vaultapi.TLSConfig{
+		CACert:        manager.CACert,
+		ClientCert:    manager.ClientCert,
+		ClientKey:     manager.ClientKey,
+		TLSServerName: manager.ServerName,
+		Insecure:      manager.Insecure,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure vault TLS: %s", err)
+	}
+
+	api, err := vaultapi.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct vault client: %s", err)
+	}
+
+	return &vaultClient{api: api}, nil
+}
+
+func (client *vaultClient) setToken(token string) {
+	client.api.SetToken(token)
+}
+
+func (client *vaultClient) authenticateWithApprole(authBackend string, roleID string, secretID string) error {
+	if authBackend == "" {
+		authBackend = "approle"
+	}
+
+	secret, err := client.api.Logical().Write(fmt.Sprintf("auth/%s/login", authBackend), map[string]interface{}{
+		"role_id":   roleID,
+		"secret_id": secretID,
+	})
+	if err != nil {
+		return fmt.Errorf("approle authentication failed: %s", err)
+	}
+
+	if secret == nil || secret.Auth == nil {
+		return fmt.Errorf("approle authentication returned no token")
+	}
+
+	client.api.SetToken(secret.Auth.ClientToken)
+
+	return nil
+}
+
+func (client *vaultClient) read(path string) (interface{}, bool, time.Duration, error) {
+	secret, err := client.api.Logical().Read(path)
+	if err != nil {
+		return nil, false, 0, fmt.Errorf("failed to read %s from vault: %s", path, err)
+	}
+
+	if secret == nil || secret.Data == nil {
+		return nil, false, 0, nil
+	}
+
+	value, found := secret.Data["value"]
+	if !found {
+		value = secret.Data
+	}
+
+	return value, true, time.Duration(secret.LeaseDuration) * time.Second, nil
+}
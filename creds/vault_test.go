@@ -0,0 +1,116 @@
+package creds
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pivotal-golang/clock/fakeclock"
+	"github.com/pivotal-golang/lager/lagertest"
+)
+
+type fakeVaultReader struct {
+	reads int
+	value interface{}
+	found bool
+	lease time.Duration
+	err   error
+}
+
+func (fake *fakeVaultReader) read(path string) (interface{}, bool, time.Duration, error) {
+	fake.reads++
+	return fake.value, fake.found, fake.lease, fake.err
+}
+
+func newTestVaultSecrets(reader vaultReader, fakeClock *fakeclock.FakeClock) *vaultSecrets {
+	return &vaultSecrets{
+		logger:   lagertest.NewTestLogger("vault"),
+		client:   reader,
+		prefix:   "/concourse",
+		team:     "main",
+		pipeline: "",
+		clock:    fakeClock,
+		cache:    map[string]vaultCacheEntry{},
+	}
+}
+
+func TestVaultSecretsCachesWithinLeaseDuration(t *testing.T) {
+	reader := &fakeVaultReader{value: "super-secret", found: true, lease: time.Minute}
+	fakeClock := fakeclock.NewFakeClock(time.Now())
+	secrets := newTestVaultSecrets(reader, fakeClock)
+
+	value, found, err := secrets.Get("api-key")
+	if err != nil || !found || value != "super-secret" {
+		t.Fatalf("got (%v, %v, %v), want (super-secret, true, nil)", value, found, err)
+	}
+
+	fakeClock.Increment(30 * time.Second)
+
+	value, found, err = secrets.Get("api-key")
+	if err != nil || !found || value != "super-secret" {
+		t.Fatalf("got (%v, %v, %v), want (super-secret, true, nil)", value, found, err)
+	}
+
+	if reader.reads != 1 {
+		t.Fatalf("expected the cached value to be served without a second read, got %d reads", reader.reads)
+	}
+}
+
+func TestVaultSecretsRereadsOnceLeaseExpires(t *testing.T) {
+	reader := &fakeVaultReader{value: "super-secret", found: true, lease: time.Minute}
+	fakeClock := fakeclock.NewFakeClock(time.Now())
+	secrets := newTestVaultSecrets(reader, fakeClock)
+
+	if _, _, err := secrets.Get("api-key"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	fakeClock.Increment(time.Minute + time.Second)
+
+	if _, _, err := secrets.Get("api-key"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if reader.reads != 2 {
+		t.Fatalf("expected the expired entry to be re-read, got %d reads", reader.reads)
+	}
+}
+
+func TestVaultSecretsDoesNotCacheWithoutALease(t *testing.T) {
+	reader := &fakeVaultReader{value: "super-secret", found: true, lease: 0}
+	fakeClock := fakeclock.NewFakeClock(time.Now())
+	secrets := newTestVaultSecrets(reader, fakeClock)
+
+	secrets.Get("api-key")
+	secrets.Get("api-key")
+
+	if reader.reads != 2 {
+		t.Fatalf("expected every call to re-read a leaseless secret, got %d reads", reader.reads)
+	}
+}
+
+func TestVaultSecretsNotFound(t *testing.T) {
+	reader := &fakeVaultReader{found: false}
+	fakeClock := fakeclock.NewFakeClock(time.Now())
+	secrets := newTestVaultSecrets(reader, fakeClock)
+
+	_, found, err := secrets.Get("api-key")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if found {
+		t.Fatalf("expected not found")
+	}
+}
+
+func TestVaultSecretsPropagatesReadError(t *testing.T) {
+	reader := &fakeVaultReader{err: errors.New("vault is sealed")}
+	fakeClock := fakeclock.NewFakeClock(time.Now())
+	secrets := newTestVaultSecrets(reader, fakeClock)
+
+	_, _, err := secrets.Get("api-key")
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
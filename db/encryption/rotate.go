@@ -0,0 +1,82 @@
+package encryption
+
+import (
+	"os"
+
+	"github.com/pivotal-golang/lager"
+)
+
+// Table is an encrypted table the db package knows how to walk, keyed by
+// whatever primary key makes sense for that table (pipelines, resources,
+// teams, build_events, ...).
+type Table interface {
+	// Name is used only for logging.
+	Name() string
+
+	// RotateBatch decrypts up to limit rows still stamped with oldKey's
+	// KeyHash using oldKey, re-encrypts them with newKey, and updates
+	// their nonce and encrypted_at_key_hash columns. It returns the
+	// number of rows it touched; Rotator keeps calling RotateBatch until
+	// it returns 0, so a crash or restart mid-rotation just resumes from
+	// whatever rows are still stamped with the old hash.
+	RotateBatch(oldKey Strategy, newKey Strategy, limit int) (int, error)
+}
+
+// Rotator re-encrypts every row of every encrypted Table from OldKey to
+// NewKey, a row batch at a time, so that an ATC can be rolled from one
+// --encryptionKey to another without downtime. It's intended to be run as
+// an ifrit.Runner alongside the other background members in main.go,
+// gated on --oldEncryptionKey being set.
+//
+// Once rotation finishes, Run blocks on signals rather than returning, so
+// that it doesn't trip grouper.NewParallel into tearing down the rest of
+// the members just because key rotation has nothing left to do.
+type Rotator struct {
+	Logger lager.Logger
+
+	OldKey Strategy
+	NewKey Strategy
+
+	Tables []Table
+
+	BatchSize int
+}
+
+func (rotator Rotator) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	batchSize := rotator.BatchSize
+	if batchSize == 0 {
+		batchSize = 100
+	}
+
+	for _, table := range rotator.Tables {
+		logger := rotator.Logger.Session("rotate", lager.Data{"table": table.Name()})
+
+		for {
+			select {
+			case <-signals:
+				return nil
+			default:
+			}
+
+			rotated, err := table.RotateBatch(rotator.OldKey, rotator.NewKey, batchSize)
+			if err != nil {
+				logger.Error("failed-to-rotate-batch", err)
+				return err
+			}
+
+			if rotated == 0 {
+				break
+			}
+
+			logger.Debug("rotated-batch", lager.Data{"rows": rotated})
+		}
+
+		logger.Info("done")
+	}
+
+	<-signals
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package encryption
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pivotal-golang/lager/lagertest"
+)
+
+type fakeTable struct {
+	name string
+
+	rowsLeft int
+	batches  []int
+	err      error
+
+	// emptied, if non-nil, is closed when RotateBatch finds no rows left.
+	emptied chan struct{}
+}
+
+func (fake *fakeTable) Name() string { return fake.name }
+
+func (fake *fakeTable) RotateBatch(oldKey Strategy, newKey Strategy, limit int) (int, error) {
+	if fake.err != nil {
+		return 0, fake.err
+	}
+
+	rotated := fake.rowsLeft
+	if rotated > limit {
+		rotated = limit
+	}
+
+	fake.rowsLeft -= rotated
+	fake.batches = append(fake.batches, rotated)
+
+	if rotated == 0 && fake.emptied != nil {
+		close(fake.emptied)
+	}
+
+	return rotated, nil
+}
+
+func TestRotatorBatchesUntilEachTableIsEmpty(t *testing.T) {
+	tableA := &fakeTable{name: "a", rowsLeft: 5}
+	tableBEmptied := make(chan struct{})
+	tableB := &fakeTable{name: "b", rowsLeft: 2, emptied: tableBEmptied}
+
+	rotator := Rotator{
+		Logger:    lagertest.NewTestLogger("rotate"),
+		OldKey:    NoEncryption{},
+		NewKey:    NoEncryption{},
+		Tables:    []Table{tableA, tableB},
+		BatchSize: 2,
+	}
+
+	signals := make(chan os.Signal)
+	ready := make(chan struct{})
+
+	done := make(chan error, 1)
+	go func() { done <- rotator.Run(signals, ready) }()
+
+	<-ready
+
+	// Run stops between batches once signaled, so only signal after the
+	// last table has been drained.
+	<-tableBEmptied
+
+	close(signals)
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got, want := tableA.batches, []int{2, 2, 1, 0}; !equal(got, want) {
+		t.Fatalf("table a batches = %v, want %v", got, want)
+	}
+
+	if got, want := tableB.batches, []int{2, 0}; !equal(got, want) {
+		t.Fatalf("table b batches = %v, want %v", got, want)
+	}
+}
+
+func TestRotatorStopsOnTableError(t *testing.T) {
+	failing := &fakeTable{name: "a", rowsLeft: 5, err: errBoom}
+
+	rotator := Rotator{
+		Logger:    lagertest.NewTestLogger("rotate"),
+		OldKey:    NoEncryption{},
+		NewKey:    NoEncryption{},
+		Tables:    []Table{failing},
+		BatchSize: 2,
+	}
+
+	signals := make(chan os.Signal)
+	ready := make(chan struct{})
+
+	err := rotator.Run(signals, ready)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestRotatorBlocksOnSignalsOnceDone(t *testing.T) {
+	rotator := Rotator{
+		Logger:    lagertest.NewTestLogger("rotate"),
+		OldKey:    NoEncryption{},
+		NewKey:    NoEncryption{},
+		Tables:    []Table{&fakeTable{name: "a", rowsLeft: 0}},
+		BatchSize: 2,
+	}
+
+	signals := make(chan os.Signal, 1)
+	ready := make(chan struct{})
+
+	done := make(chan error, 1)
+	go func() { done <- rotator.Run(signals, ready) }()
+
+	<-ready
+
+	select {
+	case <-done:
+		t.Fatalf("Run returned before being signaled, which would tear down the rest of the grouper.NewParallel members")
+	default:
+	}
+
+	signals <- os.Interrupt
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+var errBoom = &rotateError{"boom"}
+
+type rotateError struct{ msg string }
+
+func (e *rotateError) Error() string { return e.msg }
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
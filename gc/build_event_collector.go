@@ -0,0 +1,42 @@
+package gc
+
+import (
+	"fmt"
+
+	"github.com/pivotal-golang/lager"
+
+	"github.com/concourse/atc/metric"
+)
+
+// BuildEventDB is implemented by the db package. Build events live in a
+// per-build partition table (build_events_<id>) so that pruning a build's
+// events is a cheap DROP TABLE rather than a slow DELETE.
+type BuildEventDB interface {
+	FindBuildsWithExpiredEvents() ([]int, error)
+	DropBuildEventsPartition(buildID int) error
+}
+
+// BuildEventCollector drops the event partition table for any build whose
+// retention period (configured per-pipeline today) has elapsed.
+type BuildEventCollector struct {
+	Logger lager.Logger
+
+	BuildEventDB BuildEventDB
+}
+
+func (collector BuildEventCollector) Run() error {
+	buildIDs, err := collector.BuildEventDB.FindBuildsWithExpiredEvents()
+	if err != nil {
+		return fmt.Errorf("failed to find builds with expired events: %s", err)
+	}
+
+	for _, buildID := range buildIDs {
+		if err := collector.BuildEventDB.DropBuildEventsPartition(buildID); err != nil {
+			collector.Logger.Error("failed-to-drop-build-events-partition", err, lager.Data{"build": buildID})
+			metric.GCErrors.Inc()
+			continue
+		}
+	}
+
+	return nil
+}
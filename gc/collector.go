@@ -0,0 +1,8 @@
+package gc
+
+// Collector is a single GC sweep: reconcile containers, reap orphaned
+// volumes, drop expired build event partitions, etc. Each one is wrapped
+// in its own Runner so it can run on its own interval.
+type Collector interface {
+	Run() error
+}
@@ -0,0 +1,106 @@
+package gc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pivotal-golang/lager"
+
+	"github.com/concourse/atc/metric"
+)
+
+// ContainerDB is implemented by the db package. It's the subset of
+// container bookkeeping the collector needs: the DB's view of what
+// containers should exist, and how to mark one destroying/destroyed.
+type ContainerDB interface {
+	FindContainersMarkedForDeletion() ([]SavedContainer, error)
+	FindOrphanedContainers(oneOffGracePeriod, missingGracePeriod time.Duration) ([]SavedContainer, error)
+	ReapContainer(handle string) error
+}
+
+// SavedContainer is a container as the DB knows it.
+type SavedContainer struct {
+	Handle     string
+	WorkerName string
+}
+
+// WorkerGarden is implemented per-worker by the worker package; it's the
+// subset of the garden client the collector needs in order to destroy a
+// container on that worker.
+type WorkerGarden interface {
+	Lookup(handle string) (ContainerDestroyer, error)
+}
+
+// ContainerDestroyer destroys a single garden container.
+type ContainerDestroyer interface {
+	Destroy() error
+}
+
+// ContainerWorkerProvider resolves a worker by name to the garden client
+// the collector uses to actually destroy containers there.
+type ContainerWorkerProvider interface {
+	FindWorker(name string) (WorkerGarden, bool, error)
+}
+
+// ContainerCollector destroys the containers the DB lists as due to go:
+// containers the DB has marked for deletion (builds/checks that finished),
+// and containers whose pipeline/job/resource no longer exists at all
+// (orphans), once they've outlived the relevant grace period. It works
+// from the DB's records only: it does not list the containers each worker
+// reports, so a container on a worker that the DB has no record of is
+// not destroyed.
+type ContainerCollector struct {
+	Logger lager.Logger
+
+	ContainerDB ContainerDB
+	Workers     ContainerWorkerProvider
+
+	OneOffGracePeriod  time.Duration
+	MissingGracePeriod time.Duration
+}
+
+func (collector ContainerCollector) Run() error {
+	marked, err := collector.ContainerDB.FindContainersMarkedForDeletion()
+	if err != nil {
+		return fmt.Errorf("failed to find containers marked for deletion: %s", err)
+	}
+
+	orphaned, err := collector.ContainerDB.FindOrphanedContainers(collector.OneOffGracePeriod, collector.MissingGracePeriod)
+	if err != nil {
+		return fmt.Errorf("failed to find orphaned containers: %s", err)
+	}
+
+	destroyed := 0
+
+	for _, container := range append(marked, orphaned...) {
+		if err := collector.destroy(container); err != nil {
+			collector.Logger.Error("failed-to-destroy-container", err, lager.Data{"handle": container.Handle})
+			metric.GCErrors.Inc()
+			continue
+		}
+
+		destroyed++
+	}
+
+	metric.GCContainersDestroyed.Add(float64(destroyed))
+
+	return nil
+}
+
+func (collector ContainerCollector) destroy(container SavedContainer) error {
+	worker, found, err := collector.Workers.FindWorker(container.WorkerName)
+	if err != nil {
+		return fmt.Errorf("failed to find worker %s: %s", container.WorkerName, err)
+	}
+
+	if found {
+		gardenContainer, err := worker.Lookup(container.Handle)
+		if err == nil {
+			if err := gardenContainer.Destroy(); err != nil {
+				return fmt.Errorf("failed to destroy garden container: %s", err)
+			}
+		}
+	}
+
+	return collector.ContainerDB.ReapContainer(container.Handle)
+}
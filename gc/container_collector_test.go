@@ -0,0 +1,140 @@
+package gc_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pivotal-golang/lager/lagertest"
+
+	"github.com/concourse/atc/gc"
+)
+
+type fakeContainerDB struct {
+	marked   []gc.SavedContainer
+	orphaned []gc.SavedContainer
+	findErr  error
+
+	reaped  []string
+	reapErr map[string]error
+}
+
+func (fake *fakeContainerDB) FindContainersMarkedForDeletion() ([]gc.SavedContainer, error) {
+	return fake.marked, fake.findErr
+}
+
+func (fake *fakeContainerDB) FindOrphanedContainers(time.Duration, time.Duration) ([]gc.SavedContainer, error) {
+	return fake.orphaned, nil
+}
+
+func (fake *fakeContainerDB) ReapContainer(handle string) error {
+	fake.reaped = append(fake.reaped, handle)
+	return fake.reapErr[handle]
+}
+
+type fakeContainerWorkerProvider struct {
+	workers map[string]gc.WorkerGarden
+}
+
+func (fake *fakeContainerWorkerProvider) FindWorker(name string) (gc.WorkerGarden, bool, error) {
+	worker, found := fake.workers[name]
+	return worker, found, nil
+}
+
+type fakeWorkerGarden struct {
+	destroyErr map[string]error
+}
+
+func (fake *fakeWorkerGarden) Lookup(handle string) (gc.ContainerDestroyer, error) {
+	return &fakeContainerDestroyer{handle: handle, err: fake.destroyErr[handle]}, nil
+}
+
+type fakeContainerDestroyer struct {
+	handle string
+	err    error
+}
+
+func (fake *fakeContainerDestroyer) Destroy() error { return fake.err }
+
+func TestContainerCollectorDestroysMarkedAndOrphanedContainers(t *testing.T) {
+	db := &fakeContainerDB{
+		marked:   []gc.SavedContainer{{Handle: "marked-1", WorkerName: "worker-1"}},
+		orphaned: []gc.SavedContainer{{Handle: "orphaned-1", WorkerName: "worker-1"}},
+	}
+
+	collector := gc.ContainerCollector{
+		Logger:      lagertest.NewTestLogger("container-collector"),
+		ContainerDB: db,
+		Workers: &fakeContainerWorkerProvider{
+			workers: map[string]gc.WorkerGarden{"worker-1": &fakeWorkerGarden{}},
+		},
+	}
+
+	if err := collector.Run(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(db.reaped) != 2 {
+		t.Fatalf("expected both containers to be reaped, got %v", db.reaped)
+	}
+}
+
+func TestContainerCollectorReapsEvenWhenWorkerIsGone(t *testing.T) {
+	db := &fakeContainerDB{
+		orphaned: []gc.SavedContainer{{Handle: "orphaned-1", WorkerName: "missing-worker"}},
+	}
+
+	collector := gc.ContainerCollector{
+		Logger:      lagertest.NewTestLogger("container-collector"),
+		ContainerDB: db,
+		Workers:     &fakeContainerWorkerProvider{workers: map[string]gc.WorkerGarden{}},
+	}
+
+	if err := collector.Run(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(db.reaped) != 1 || db.reaped[0] != "orphaned-1" {
+		t.Fatalf("expected the container to still be reaped from the DB, got %v", db.reaped)
+	}
+}
+
+func TestContainerCollectorContinuesPastAReapFailure(t *testing.T) {
+	db := &fakeContainerDB{
+		orphaned: []gc.SavedContainer{
+			{Handle: "bad", WorkerName: "worker-1"},
+			{Handle: "good", WorkerName: "worker-1"},
+		},
+		reapErr: map[string]error{"bad": errors.New("db is down")},
+	}
+
+	collector := gc.ContainerCollector{
+		Logger:      lagertest.NewTestLogger("container-collector"),
+		ContainerDB: db,
+		Workers: &fakeContainerWorkerProvider{
+			workers: map[string]gc.WorkerGarden{"worker-1": &fakeWorkerGarden{}},
+		},
+	}
+
+	if err := collector.Run(); err != nil {
+		t.Fatalf("expected Run to log-and-continue rather than fail outright: %s", err)
+	}
+
+	if len(db.reaped) != 2 {
+		t.Fatalf("expected both containers to be attempted, got %v", db.reaped)
+	}
+}
+
+func TestContainerCollectorPropagatesFindErrors(t *testing.T) {
+	db := &fakeContainerDB{findErr: errors.New("db is down")}
+
+	collector := gc.ContainerCollector{
+		Logger:      lagertest.NewTestLogger("container-collector"),
+		ContainerDB: db,
+		Workers:     &fakeContainerWorkerProvider{workers: map[string]gc.WorkerGarden{}},
+	}
+
+	if err := collector.Run(); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
@@ -0,0 +1,43 @@
+package gc
+
+import (
+	"fmt"
+
+	"github.com/pivotal-golang/lager"
+
+	"github.com/concourse/atc/metric"
+)
+
+// ResourceCacheDB is implemented by the db package.
+type ResourceCacheDB interface {
+	FindUnreferencedResourceCaches() ([]int, error)
+	DeleteResourceCache(id int) error
+}
+
+// ResourceCacheCollector deletes resource_cache rows that no build,
+// resource config, or in-flight check is using any more. Like the other
+// collectors it runs on its own Runner and interval, with no ordering
+// relative to ContainerCollector or VolumeCollector, so a cache's row may
+// go away before its backing volume has been reaped.
+type ResourceCacheCollector struct {
+	Logger lager.Logger
+
+	ResourceCacheDB ResourceCacheDB
+}
+
+func (collector ResourceCacheCollector) Run() error {
+	ids, err := collector.ResourceCacheDB.FindUnreferencedResourceCaches()
+	if err != nil {
+		return fmt.Errorf("failed to find unreferenced resource caches: %s", err)
+	}
+
+	for _, id := range ids {
+		if err := collector.ResourceCacheDB.DeleteResourceCache(id); err != nil {
+			collector.Logger.Error("failed-to-delete-resource-cache", err, lager.Data{"id": id})
+			metric.GCErrors.Inc()
+			continue
+		}
+	}
+
+	return nil
+}
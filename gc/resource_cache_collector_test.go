@@ -0,0 +1,77 @@
+package gc_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pivotal-golang/lager/lagertest"
+
+	"github.com/concourse/atc/gc"
+)
+
+type fakeResourceCacheDB struct {
+	ids     []int
+	findErr error
+
+	deleted   []int
+	deleteErr map[int]error
+}
+
+func (fake *fakeResourceCacheDB) FindUnreferencedResourceCaches() ([]int, error) {
+	return fake.ids, fake.findErr
+}
+
+func (fake *fakeResourceCacheDB) DeleteResourceCache(id int) error {
+	fake.deleted = append(fake.deleted, id)
+	return fake.deleteErr[id]
+}
+
+func TestResourceCacheCollectorDeletesUnreferencedCaches(t *testing.T) {
+	db := &fakeResourceCacheDB{ids: []int{1, 2, 3}}
+
+	collector := gc.ResourceCacheCollector{
+		Logger:          lagertest.NewTestLogger("resource-cache-collector"),
+		ResourceCacheDB: db,
+	}
+
+	if err := collector.Run(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(db.deleted) != 3 {
+		t.Fatalf("expected all 3 caches to be deleted, got %v", db.deleted)
+	}
+}
+
+func TestResourceCacheCollectorContinuesPastADeleteFailure(t *testing.T) {
+	db := &fakeResourceCacheDB{
+		ids:       []int{1, 2, 3},
+		deleteErr: map[int]error{2: errors.New("still referenced")},
+	}
+
+	collector := gc.ResourceCacheCollector{
+		Logger:          lagertest.NewTestLogger("resource-cache-collector"),
+		ResourceCacheDB: db,
+	}
+
+	if err := collector.Run(); err != nil {
+		t.Fatalf("expected Run to log-and-continue rather than fail outright: %s", err)
+	}
+
+	if len(db.deleted) != 3 {
+		t.Fatalf("expected every id to still be attempted, got %v", db.deleted)
+	}
+}
+
+func TestResourceCacheCollectorPropagatesFindErrors(t *testing.T) {
+	db := &fakeResourceCacheDB{findErr: errors.New("db is down")}
+
+	collector := gc.ResourceCacheCollector{
+		Logger:          lagertest.NewTestLogger("resource-cache-collector"),
+		ResourceCacheDB: db,
+	}
+
+	if err := collector.Run(); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
@@ -0,0 +1,76 @@
+package gc
+
+import (
+	"os"
+	"time"
+
+	"github.com/pivotal-golang/clock"
+	"github.com/pivotal-golang/lager"
+
+	"github.com/concourse/atc/metric"
+)
+
+// LockFactory is implemented by the db package. Every ATC in an HA
+// deployment runs the same set of Runners, so each sweep takes a
+// Postgres advisory lock (keyed by LockName) before doing anything;
+// whichever ATC gets the lock runs the sweep, and the rest skip that
+// tick.
+type LockFactory interface {
+	AcquireLock(name string) (Lock, bool, error)
+}
+
+// Lock is held for the duration of a single sweep.
+type Lock interface {
+	Release() error
+}
+
+// Runner wraps a Collector as an ifrit.Runner, running it once immediately
+// and then again on every tick of Interval, same as builds.TrackerRunner
+// and syslog.Runner.
+type Runner struct {
+	Logger lager.Logger
+
+	Collector Collector
+
+	Locks    LockFactory
+	LockName string
+
+	Interval time.Duration
+	Clock    clock.Clock
+}
+
+func (runner Runner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	ticker := runner.Clock.NewTicker(runner.Interval)
+	defer ticker.Stop()
+
+	for {
+		runner.sweep()
+
+		select {
+		case <-ticker.C():
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+func (runner Runner) sweep() {
+	lock, acquired, err := runner.Locks.AcquireLock(runner.LockName)
+	if err != nil {
+		runner.Logger.Error("failed-to-acquire-lock", err)
+		return
+	}
+
+	if !acquired {
+		return
+	}
+
+	defer lock.Release()
+
+	if err := runner.Collector.Run(); err != nil {
+		runner.Logger.Error("failed-to-run-collector", err)
+		metric.GCErrors.Inc()
+	}
+}
@@ -0,0 +1,82 @@
+package gc
+
+import (
+	"fmt"
+
+	"github.com/pivotal-golang/lager"
+
+	"github.com/concourse/atc/metric"
+)
+
+// VolumeDB is implemented by the db package.
+type VolumeDB interface {
+	FindOrphanedVolumes() ([]SavedVolume, error)
+	ReapVolume(handle string) error
+}
+
+// SavedVolume is a volume as the DB knows it.
+type SavedVolume struct {
+	Handle     string
+	WorkerName string
+}
+
+// BaggageclaimClient is the subset of a baggageclaim client the collector
+// needs in order to destroy a volume on a particular worker.
+type BaggageclaimClient interface {
+	DestroyVolume(handle string) error
+}
+
+// VolumeWorkerProvider resolves a worker by name to its baggageclaim
+// client.
+type VolumeWorkerProvider interface {
+	FindBaggageclaim(workerName string) (BaggageclaimClient, bool, error)
+}
+
+// VolumeCollector destroys volumes that the DB no longer has any
+// container, resource cache, or resource config referencing, mirroring
+// ContainerCollector's find-in-the-DB-then-destroy shape but against
+// baggageclaim instead of garden.
+type VolumeCollector struct {
+	Logger lager.Logger
+
+	VolumeDB VolumeDB
+	Workers  VolumeWorkerProvider
+}
+
+func (collector VolumeCollector) Run() error {
+	orphaned, err := collector.VolumeDB.FindOrphanedVolumes()
+	if err != nil {
+		return fmt.Errorf("failed to find orphaned volumes: %s", err)
+	}
+
+	orphanedCount := 0
+
+	for _, volume := range orphaned {
+		if err := collector.destroy(volume); err != nil {
+			collector.Logger.Error("failed-to-destroy-volume", err, lager.Data{"handle": volume.Handle})
+			metric.GCErrors.Inc()
+			continue
+		}
+
+		orphanedCount++
+	}
+
+	metric.GCVolumesOrphaned.Add(float64(orphanedCount))
+
+	return nil
+}
+
+func (collector VolumeCollector) destroy(volume SavedVolume) error {
+	baggageclaim, found, err := collector.Workers.FindBaggageclaim(volume.WorkerName)
+	if err != nil {
+		return fmt.Errorf("failed to find baggageclaim on %s: %s", volume.WorkerName, err)
+	}
+
+	if found {
+		if err := baggageclaim.DestroyVolume(volume.Handle); err != nil {
+			return fmt.Errorf("failed to destroy volume: %s", err)
+		}
+	}
+
+	return collector.VolumeDB.ReapVolume(volume.Handle)
+}
@@ -0,0 +1,107 @@
+package gc_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pivotal-golang/lager/lagertest"
+
+	"github.com/concourse/atc/gc"
+)
+
+type fakeVolumeDB struct {
+	orphaned []gc.SavedVolume
+	findErr  error
+
+	reaped  []string
+	reapErr map[string]error
+}
+
+func (fake *fakeVolumeDB) FindOrphanedVolumes() ([]gc.SavedVolume, error) {
+	return fake.orphaned, fake.findErr
+}
+
+func (fake *fakeVolumeDB) ReapVolume(handle string) error {
+	fake.reaped = append(fake.reaped, handle)
+	return fake.reapErr[handle]
+}
+
+type fakeVolumeWorkerProvider struct {
+	workers map[string]gc.BaggageclaimClient
+}
+
+func (fake *fakeVolumeWorkerProvider) FindBaggageclaim(name string) (gc.BaggageclaimClient, bool, error) {
+	baggageclaim, found := fake.workers[name]
+	return baggageclaim, found, nil
+}
+
+type fakeBaggageclaimClient struct {
+	destroyErr map[string]error
+}
+
+func (fake *fakeBaggageclaimClient) DestroyVolume(handle string) error {
+	return fake.destroyErr[handle]
+}
+
+func TestVolumeCollectorDestroysOrphanedVolumes(t *testing.T) {
+	db := &fakeVolumeDB{
+		orphaned: []gc.SavedVolume{{Handle: "v1", WorkerName: "worker-1"}},
+	}
+
+	collector := gc.VolumeCollector{
+		Logger:   lagertest.NewTestLogger("volume-collector"),
+		VolumeDB: db,
+		Workers: &fakeVolumeWorkerProvider{
+			workers: map[string]gc.BaggageclaimClient{"worker-1": &fakeBaggageclaimClient{}},
+		},
+	}
+
+	if err := collector.Run(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(db.reaped) != 1 || db.reaped[0] != "v1" {
+		t.Fatalf("expected v1 to be reaped, got %v", db.reaped)
+	}
+}
+
+func TestVolumeCollectorContinuesPastADestroyFailure(t *testing.T) {
+	db := &fakeVolumeDB{
+		orphaned: []gc.SavedVolume{
+			{Handle: "bad", WorkerName: "worker-1"},
+			{Handle: "good", WorkerName: "worker-1"},
+		},
+	}
+
+	collector := gc.VolumeCollector{
+		Logger:   lagertest.NewTestLogger("volume-collector"),
+		VolumeDB: db,
+		Workers: &fakeVolumeWorkerProvider{
+			workers: map[string]gc.BaggageclaimClient{
+				"worker-1": &fakeBaggageclaimClient{destroyErr: map[string]error{"bad": errors.New("boom")}},
+			},
+		},
+	}
+
+	if err := collector.Run(); err != nil {
+		t.Fatalf("expected Run to log-and-continue rather than fail outright: %s", err)
+	}
+
+	if len(db.reaped) != 1 || db.reaped[0] != "good" {
+		t.Fatalf("expected only the successfully-destroyed volume to be reaped, got %v", db.reaped)
+	}
+}
+
+func TestVolumeCollectorPropagatesFindErrors(t *testing.T) {
+	db := &fakeVolumeDB{findErr: errors.New("db is down")}
+
+	collector := gc.VolumeCollector{
+		Logger:   lagertest.NewTestLogger("volume-collector"),
+		VolumeDB: db,
+		Workers:  &fakeVolumeWorkerProvider{workers: map[string]gc.BaggageclaimClient{}},
+	}
+
+	if err := collector.Run(); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
@@ -0,0 +1,41 @@
+package gc
+
+import (
+	"fmt"
+
+	"github.com/pivotal-golang/lager"
+
+	"github.com/concourse/atc/metric"
+)
+
+// WorkerDB is implemented by the db package.
+type WorkerDB interface {
+	FindStalledWorkers() ([]string, error)
+	DeleteWorker(name string) error
+}
+
+// WorkerCollector removes worker records that have stopped heartbeating
+// (their ttl has expired) but were never cleanly deregistered, so stale
+// workers don't keep being selected for placement or reported in the API.
+type WorkerCollector struct {
+	Logger lager.Logger
+
+	WorkerDB WorkerDB
+}
+
+func (collector WorkerCollector) Run() error {
+	stalled, err := collector.WorkerDB.FindStalledWorkers()
+	if err != nil {
+		return fmt.Errorf("failed to find stalled workers: %s", err)
+	}
+
+	for _, name := range stalled {
+		if err := collector.WorkerDB.DeleteWorker(name); err != nil {
+			collector.Logger.Error("failed-to-delete-worker", err, lager.Data{"worker": name})
+			metric.GCErrors.Inc()
+			continue
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package gc_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pivotal-golang/lager/lagertest"
+
+	"github.com/concourse/atc/gc"
+)
+
+type fakeWorkerDB struct {
+	stalled []string
+	findErr error
+
+	deleted   []string
+	deleteErr map[string]error
+}
+
+func (fake *fakeWorkerDB) FindStalledWorkers() ([]string, error) {
+	return fake.stalled, fake.findErr
+}
+
+func (fake *fakeWorkerDB) DeleteWorker(name string) error {
+	fake.deleted = append(fake.deleted, name)
+	return fake.deleteErr[name]
+}
+
+func TestWorkerCollectorDeletesStalledWorkers(t *testing.T) {
+	db := &fakeWorkerDB{stalled: []string{"worker-1", "worker-2"}}
+
+	collector := gc.WorkerCollector{
+		Logger:   lagertest.NewTestLogger("worker-collector"),
+		WorkerDB: db,
+	}
+
+	if err := collector.Run(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(db.deleted) != 2 {
+		t.Fatalf("expected both workers to be deleted, got %v", db.deleted)
+	}
+}
+
+func TestWorkerCollectorContinuesPastADeleteFailure(t *testing.T) {
+	db := &fakeWorkerDB{
+		stalled:   []string{"worker-1", "worker-2"},
+		deleteErr: map[string]error{"worker-1": errors.New("still running a build")},
+	}
+
+	collector := gc.WorkerCollector{
+		Logger:   lagertest.NewTestLogger("worker-collector"),
+		WorkerDB: db,
+	}
+
+	if err := collector.Run(); err != nil {
+		t.Fatalf("expected Run to log-and-continue rather than fail outright: %s", err)
+	}
+
+	if len(db.deleted) != 2 {
+		t.Fatalf("expected both workers to still be attempted, got %v", db.deleted)
+	}
+}
+
+func TestWorkerCollectorPropagatesFindErrors(t *testing.T) {
+	db := &fakeWorkerDB{findErr: errors.New("db is down")}
+
+	collector := gc.WorkerCollector{
+		Logger:   lagertest.NewTestLogger("worker-collector"),
+		WorkerDB: db,
+	}
+
+	if err := collector.Run(); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
@@ -0,0 +1,50 @@
+package metric
+
+import "sync"
+
+// Counter is a monotonically-increasing metric, e.g. "how many containers
+// has the gc package destroyed since startup". It's emitted as a delta on
+// every Add/Inc so Riemann sees a stream of increments, while also
+// accumulating a running total a PrometheusEmitter can expose directly as
+// a counter.
+type Counter struct {
+	name string
+
+	mu    sync.Mutex
+	total float64
+}
+
+func NewCounter(name string) *Counter {
+	return &Counter{name: name}
+}
+
+func (counter *Counter) Inc() {
+	counter.Add(1)
+}
+
+func (counter *Counter) Add(delta float64) {
+	counter.mu.Lock()
+	counter.total += delta
+	counter.mu.Unlock()
+
+	emit(Event{
+		Name:  counter.name,
+		Value: delta,
+	})
+}
+
+// Total returns the running total, which is what a PrometheusEmitter
+// reports (Prometheus counters are cumulative, unlike Riemann's
+// point-in-time metrics).
+func (counter *Counter) Total() float64 {
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+
+	return counter.total
+}
+
+var (
+	GCContainersDestroyed = NewCounter("gc.containers.destroyed")
+	GCVolumesOrphaned     = NewCounter("gc.volumes.orphaned")
+	GCErrors              = NewCounter("gc.errors")
+)
@@ -0,0 +1,83 @@
+package metric
+
+import "time"
+
+// SchedulingJobDuration reports how long the scheduler spent on a single
+// job's tick.
+func SchedulingJobDuration(pipeline, job string, duration time.Duration) {
+	emit(Event{
+		Name:  "scheduling.job.duration",
+		Value: duration.Seconds(),
+		Attributes: map[string]string{
+			"pipeline": pipeline,
+			"job":      job,
+		},
+	})
+}
+
+// BuildStarted reports a build transitioning into the started state.
+func BuildStarted(pipeline, job, team string) {
+	emit(Event{
+		Name:  "build.started",
+		Value: 1,
+		Attributes: map[string]string{
+			"pipeline": pipeline,
+			"job":      job,
+			"team":     team,
+		},
+	})
+}
+
+// BuildFinished reports a build's terminal status and how long it ran.
+func BuildFinished(pipeline, job, team, status string, duration time.Duration) {
+	emit(Event{
+		Name:  "build.finished",
+		Value: duration.Seconds(),
+		Attributes: map[string]string{
+			"pipeline": pipeline,
+			"job":      job,
+			"team":     team,
+			"status":   status,
+		},
+	})
+}
+
+// HTTPResponseTime reports how long the API took to answer a single
+// request.
+func HTTPResponseTime(route, method string, status int, duration time.Duration) {
+	emit(Event{
+		Name:  "http.response_time",
+		Value: duration.Seconds(),
+		Attributes: map[string]string{
+			"route":  route,
+			"method": method,
+			"status": statusLabel(status),
+		},
+	})
+}
+
+// WorkerContainers reports how many containers a worker currently holds.
+func WorkerContainers(worker string, count int) {
+	emit(Event{
+		Name:  "worker.containers",
+		Value: float64(count),
+		Attributes: map[string]string{
+			"worker": worker,
+		},
+	})
+}
+
+func statusLabel(status int) string {
+	switch {
+	case status < 200:
+		return "1xx"
+	case status < 300:
+		return "2xx"
+	case status < 400:
+		return "3xx"
+	case status < 500:
+		return "4xx"
+	default:
+		return "5xx"
+	}
+}
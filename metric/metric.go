@@ -0,0 +1,42 @@
+package metric
+
+import (
+	"github.com/pivotal-golang/lager"
+)
+
+// Event is a single metric observation. Name is a dotted identifier (e.g.
+// "gc.containers.destroyed"); Attributes carries the labels an Emitter
+// should attach (pipeline, job, team, status, ...).
+type Event struct {
+	Name       string
+	Value      float64
+	Attributes map[string]string
+}
+
+// Emitter is implemented once per metrics backend. Initialize registers
+// zero or more of them; every Event is fanned out to all of them.
+type Emitter interface {
+	Emit(lager.Logger, Event)
+}
+
+var emitters []Emitter
+var logger lager.Logger
+
+// Initialize registers the Emitters metrics should be sent to for the
+// lifetime of the process. It replaces the single hardcoded Riemann push
+// that used to live here; Riemann and Prometheus (and any future backend)
+// are just two Emitters now; call with both to run them simultaneously.
+func Initialize(log lager.Logger, emitterList []Emitter) {
+	logger = log
+	emitters = emitterList
+}
+
+func emit(event Event) {
+	if logger == nil {
+		return
+	}
+
+	for _, emitter := range emitters {
+		go emitter.Emit(logger.Session("metric"), event)
+	}
+}
@@ -0,0 +1,170 @@
+package metric
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/pivotal-golang/lager"
+)
+
+// PrometheusEmitter exposes every Event as a Prometheus collector on a
+// /metrics endpoint, registering a new collector for a given Event name the
+// first time it's seen; collectors are keyed by name alone, with the label
+// names of the first Event of that name. metricKind picks the collector
+// type by name: the gc.* counters and "build.started" are counters that
+// accumulate; "scheduling.job.duration", "build.finished" and
+// "http.response_time" are histograms; everything else is tracked as a
+// gauge of its last value.
+type PrometheusEmitter struct {
+	mu         sync.Mutex
+	counters   map[string]*prometheus.CounterVec
+	gauges     map[string]*prometheus.GaugeVec
+	histograms map[string]*prometheus.HistogramVec
+}
+
+func NewPrometheusEmitter() *PrometheusEmitter {
+	return &PrometheusEmitter{
+		counters:   map[string]*prometheus.CounterVec{},
+		gauges:     map[string]*prometheus.GaugeVec{},
+		histograms: map[string]*prometheus.HistogramVec{},
+	}
+}
+
+// Listen starts a dedicated HTTP listener for /metrics; an empty bindIP
+// listens on all interfaces. To serve /metrics on an existing mux (the
+// debug listener) instead, call Mount rather than Listen.
+// The bind itself happens synchronously so a port already in use is
+// reported to the caller immediately, same as every other listener in
+// cmd/atc/main.go; only accepting connections happens in the background.
+func (emitter *PrometheusEmitter) Listen(bindIP string, bindPort int) error {
+	mux := http.NewServeMux()
+	emitter.Mount(mux)
+
+	addr := fmt.Sprintf("%s:%d", bindIP, bindPort)
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %s", addr, err)
+	}
+
+	go http.Serve(listener, mux)
+
+	return nil
+}
+
+// Mount registers /metrics on an existing mux, e.g. the debug listener's
+// http.DefaultServeMux, when a dedicated Prometheus listener isn't wanted.
+func (emitter *PrometheusEmitter) Mount(mux *http.ServeMux) {
+	mux.Handle("/metrics", prometheus.Handler())
+}
+
+func (emitter *PrometheusEmitter) Emit(logger lager.Logger, event Event) {
+	labelNames := make([]string, 0, len(event.Attributes))
+	labelValues := make(prometheus.Labels, len(event.Attributes))
+	for k, v := range event.Attributes {
+		labelNames = append(labelNames, k)
+		labelValues[k] = v
+	}
+
+	switch metricKind(event.Name) {
+	case counterKind:
+		emitter.counterFor(event.Name, labelNames).With(labelValues).Add(event.Value)
+	case histogramKind:
+		emitter.histogramFor(event.Name, labelNames).With(labelValues).Observe(event.Value)
+	default:
+		emitter.gaugeFor(event.Name, labelNames).With(labelValues).Set(event.Value)
+	}
+}
+
+type metricType int
+
+const (
+	gaugeKind metricType = iota
+	counterKind
+	histogramKind
+)
+
+func metricKind(name string) metricType {
+	switch name {
+	case "gc.containers.destroyed", "gc.volumes.orphaned", "gc.errors", "build.started":
+		return counterKind
+	case "scheduling.job.duration", "build.finished", "http.response_time":
+		return histogramKind
+	default:
+		return gaugeKind
+	}
+}
+
+func (emitter *PrometheusEmitter) counterFor(name string, labelNames []string) *prometheus.CounterVec {
+	emitter.mu.Lock()
+	defer emitter.mu.Unlock()
+
+	if vec, found := emitter.counters[name]; found {
+		return vec
+	}
+
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "concourse",
+		Name:      prometheusName(name),
+	}, labelNames)
+
+	prometheus.MustRegister(vec)
+	emitter.counters[name] = vec
+
+	return vec
+}
+
+func (emitter *PrometheusEmitter) gaugeFor(name string, labelNames []string) *prometheus.GaugeVec {
+	emitter.mu.Lock()
+	defer emitter.mu.Unlock()
+
+	if vec, found := emitter.gauges[name]; found {
+		return vec
+	}
+
+	vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "concourse",
+		Name:      prometheusName(name),
+	}, labelNames)
+
+	prometheus.MustRegister(vec)
+	emitter.gauges[name] = vec
+
+	return vec
+}
+
+func (emitter *PrometheusEmitter) histogramFor(name string, labelNames []string) *prometheus.HistogramVec {
+	emitter.mu.Lock()
+	defer emitter.mu.Unlock()
+
+	if vec, found := emitter.histograms[name]; found {
+		return vec
+	}
+
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "concourse",
+		Name:      prometheusName(name),
+	}, labelNames)
+
+	prometheus.MustRegister(vec)
+	emitter.histograms[name] = vec
+
+	return vec
+}
+
+func prometheusName(name string) string {
+	out := make([]byte, len(name))
+	for i := 0; i < len(name); i++ {
+		if name[i] == '.' || name[i] == '-' {
+			out[i] = '_'
+		} else {
+			out[i] = name[i]
+		}
+	}
+
+	return string(out)
+}
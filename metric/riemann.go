@@ -0,0 +1,54 @@
+package metric
+
+import (
+	"fmt"
+
+	"github.com/bigdatadev/goryman"
+	"github.com/pivotal-golang/lager"
+)
+
+// RiemannEmitter is the original (and until now, only) Emitter: it pushes
+// every Event to a Riemann server as it happens.
+type RiemannEmitter struct {
+	client *goryman.GorymanClient
+
+	host       string
+	tags       []string
+	attributes map[string]string
+}
+
+func NewRiemannEmitter(addr, host string, tags []string, attributes map[string]string) (*RiemannEmitter, error) {
+	client := goryman.NewGorymanClient(addr)
+
+	if err := client.Connect(); err != nil {
+		return nil, fmt.Errorf("failed to connect to riemann at %s: %s", addr, err)
+	}
+
+	return &RiemannEmitter{
+		client:     client,
+		host:       host,
+		tags:       tags,
+		attributes: attributes,
+	}, nil
+}
+
+func (emitter *RiemannEmitter) Emit(logger lager.Logger, event Event) {
+	attributes := map[string]string{}
+	for k, v := range emitter.attributes {
+		attributes[k] = v
+	}
+	for k, v := range event.Attributes {
+		attributes[k] = v
+	}
+
+	err := emitter.client.SendEvent(&goryman.Event{
+		Service:    event.Name,
+		Host:       emitter.host,
+		Metric:     event.Value,
+		Tags:       emitter.tags,
+		Attributes: attributes,
+	})
+	if err != nil {
+		logger.Error("failed-to-emit-to-riemann", err, lager.Data{"event": event.Name})
+	}
+}
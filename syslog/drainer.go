@@ -0,0 +1,144 @@
+package syslog
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pivotal-golang/lager"
+)
+
+// Transport selects how the Drainer dials Address.
+type Transport string
+
+const (
+	TransportTCP Transport = "tcp"
+	TransportUDP Transport = "udp"
+	TransportTLS Transport = "tls"
+)
+
+// Config configures a Drainer. Hostname identifies this ATC in the
+// messages it ships (the syslog HOSTNAME field / fluentd record's
+// "hostname" field);
+// Address is host:port of the remote syslog or fluentd forward-protocol
+// endpoint.
+type Config struct {
+	Hostname string
+
+	Address   string
+	Transport Transport
+
+	CACert string
+
+	DrainInterval time.Duration
+}
+
+// DrainCursorDB is implemented by the db package. It tracks, per build, how
+// far a Drainer has gotten through that build's event stream, in the
+// build_log_drain_cursors table, so a restart doesn't re-ship or drop
+// events.
+type DrainCursorDB interface {
+	BuildsToDrain() ([]BuildToDrain, error)
+	SaveDrainCursor(buildID int, cursor uint, complete bool) error
+}
+
+// BuildToDrain is a build with events still to ship, and the cursor into
+// its event stream this Drainer last got to. Completed is true once the
+// build itself has finished, meaning no further events will ever be
+// appended past whatever Events currently returns.
+type BuildToDrain struct {
+	BuildID   int
+	Cursor    uint
+	Completed bool
+}
+
+// BuildEventSource is implemented by the db package's build event store
+// (the same one buildserver.NewEventHandler streams to SSE clients).
+type BuildEventSource interface {
+	Events(buildID int, from uint) ([]Event, error)
+}
+
+// Event is one build log line, already rendered the way it would be sent
+// to an SSE client.
+type Event struct {
+	EventID uint
+	Origin  string
+	Payload string
+}
+
+// Writer ships a batch of events for a single build to the remote drain.
+// RFC5424Writer and FluentdWriter are the two implementations.
+type Writer interface {
+	Write(buildID int, hostname string, events []Event) error
+	Close() error
+}
+
+// Drainer is run on an interval (see Runner) and ships any events that
+// have accumulated since the last run for every build with events still
+// outstanding.
+type Drainer struct {
+	Logger lager.Logger
+
+	Cursors DrainCursorDB
+	Events  BuildEventSource
+
+	NewWriter func() (Writer, error)
+
+	Config Config
+}
+
+func (drainer Drainer) Drain() error {
+	builds, err := drainer.Cursors.BuildsToDrain()
+	if err != nil {
+		return fmt.Errorf("failed to list builds to drain: %s", err)
+	}
+
+	if len(builds) == 0 {
+		return nil
+	}
+
+	writer, err := drainer.NewWriter()
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %s", drainer.Config.Address, err)
+	}
+	defer writer.Close()
+
+	for _, build := range builds {
+		logger := drainer.Logger.Session("drain", lager.Data{"build": build.BuildID})
+
+		if err := drainer.drainBuild(writer, build); err != nil {
+			logger.Error("failed-to-drain-build", err)
+			continue
+		}
+	}
+
+	return nil
+}
+
+func (drainer Drainer) drainBuild(writer Writer, build BuildToDrain) error {
+	events, err := drainer.Events.Events(build.BuildID, build.Cursor)
+	if err != nil {
+		return fmt.Errorf("failed to fetch events: %s", err)
+	}
+
+	if len(events) == 0 {
+		if build.Completed {
+			// Nothing left to ship and the build is done, so there's
+			// nothing left to ever ship; tell the DB this build no
+			// longer needs to come back in BuildsToDrain.
+			return drainer.Cursors.SaveDrainCursor(build.BuildID, build.Cursor, true)
+		}
+
+		return nil
+	}
+
+	if err := writer.Write(build.BuildID, drainer.Config.Hostname, events); err != nil {
+		return fmt.Errorf("failed to ship events: %s", err)
+	}
+
+	cursor := events[len(events)-1].EventID + 1
+
+	// Events returns everything from the cursor forward, so if the build
+	// has already completed, what we just shipped was everything there
+	// will ever be.
+	return drainer.Cursors.SaveDrainCursor(build.BuildID, cursor, build.Completed)
+}
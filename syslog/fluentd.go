@@ -0,0 +1,53 @@
+package syslog
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"gopkg.in/vmihailenco/msgpack.v2"
+)
+
+// FluentdWriter ships events using the Fluentd forward protocol: each
+// event becomes a [tag, time, record] array, msgpack-encoded, tagged
+// "concourse.build.<build-id>".
+type FluentdWriter struct {
+	conn net.Conn
+	enc  *msgpack.Encoder
+}
+
+func NewFluentdWriter(config Config) (Writer, error) {
+	conn, err := dial(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FluentdWriter{
+		conn: conn,
+		enc:  msgpack.NewEncoder(conn),
+	}, nil
+}
+
+func (writer *FluentdWriter) Write(buildID int, hostname string, events []Event) error {
+	tag := fmt.Sprintf("concourse.build.%d", buildID)
+
+	for _, event := range events {
+		record := map[string]interface{}{
+			"hostname": hostname,
+			"origin":   event.Origin,
+			"message":  event.Payload,
+		}
+
+		entry := []interface{}{tag, time.Now().Unix(), record}
+
+		if err := writer.enc.Encode(entry); err != nil {
+			return fmt.Errorf("failed to write fluentd entry: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func (writer *FluentdWriter) Close() error {
+	return writer.conn.Close()
+}
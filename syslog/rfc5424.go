@@ -0,0 +1,72 @@
+package syslog
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"time"
+)
+
+// RFC5424Writer ships events as RFC 5424 syslog messages over TCP, UDP, or
+// TLS, one message per event.
+type RFC5424Writer struct {
+	conn net.Conn
+}
+
+func NewRFC5424Writer(config Config) (Writer, error) {
+	conn, err := dial(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RFC5424Writer{conn: conn}, nil
+}
+
+func dial(config Config) (net.Conn, error) {
+	switch config.Transport {
+	case TransportTLS:
+		tlsConfig := &tls.Config{}
+
+		if config.CACert != "" {
+			caCert, err := ioutil.ReadFile(config.CACert)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read ca cert: %s", err)
+			}
+
+			pool := x509.NewCertPool()
+			pool.AppendCertsFromPEM(caCert)
+
+			tlsConfig.RootCAs = pool
+		}
+
+		return tls.Dial("tcp", config.Address, tlsConfig)
+	case TransportUDP:
+		return net.Dial("udp", config.Address)
+	default:
+		return net.Dial("tcp", config.Address)
+	}
+}
+
+func (writer *RFC5424Writer) Write(buildID int, hostname string, events []Event) error {
+	for _, event := range events {
+		msg := fmt.Sprintf(
+			"<14>1 %s %s atc %d - - %s\n",
+			time.Now().UTC().Format(time.RFC3339),
+			hostname,
+			buildID,
+			event.Payload,
+		)
+
+		if _, err := writer.conn.Write([]byte(msg)); err != nil {
+			return fmt.Errorf("failed to write syslog message: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func (writer *RFC5424Writer) Close() error {
+	return writer.conn.Close()
+}
@@ -0,0 +1,37 @@
+package syslog
+
+import (
+	"os"
+	"time"
+
+	"github.com/pivotal-golang/clock"
+)
+
+// Runner wraps a Drainer as an ifrit.Runner, draining once immediately and
+// then again on every tick of Interval, matching builds.TrackerRunner and
+// pipelines.SyncRunner.
+type Runner struct {
+	Drainer Drainer
+
+	Interval time.Duration
+	Clock    clock.Clock
+}
+
+func (runner Runner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	ticker := runner.Clock.NewTicker(runner.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := runner.Drainer.Drain(); err != nil {
+			runner.Drainer.Logger.Error("failed-to-drain", err)
+		}
+
+		select {
+		case <-ticker.C():
+		case <-signals:
+			return nil
+		}
+	}
+}